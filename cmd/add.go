@@ -1,38 +1,50 @@
 package cmd
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
-	gh "github.com/cli/go-gh/v2"
+	"github.com/cli/go-gh/v2/pkg/auth"
 	"github.com/cli/go-gh/v2/pkg/prompter"
 	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/cli/go-gh/v2/pkg/term"
 	"github.com/ffalor/gh-wt/internal/action"
 	"github.com/ffalor/gh-wt/internal/config"
+	"github.com/ffalor/gh-wt/internal/direnv"
+	"github.com/ffalor/gh-wt/internal/editor"
 	"github.com/ffalor/gh-wt/internal/execext"
 	"github.com/ffalor/gh-wt/internal/git"
 	"github.com/ffalor/gh-wt/internal/logger"
+	"github.com/ffalor/gh-wt/internal/shell"
+	"github.com/ffalor/gh-wt/internal/tmux"
 	"github.com/ffalor/gh-wt/internal/worktree"
+	"github.com/ffalor/gh-wt/pkg/ghwt"
 	"github.com/spf13/cobra"
 )
 
 // addCmd represents the add command.
 var addCmd = &cobra.Command{
-	Use:   "add [url|name]",
+	Use:   "add [url|name]...",
 	Short: "Add a new worktree",
 	Long: `Add a new git worktree from either:
  - A GitHub pull request URL or number
  - A GitHub issue URL or number
  - A name to use for the new worktree and branch
+
+When run interactively without arguments, pick one of the repository's open pull requests.
+Several arguments create one worktree each; failures are reported at the end.
 `,
 	Aliases: []string{"create"},
-	Args:    cobra.RangeArgs(0, 1),
+	Args:    cobra.ArbitraryArgs,
 	RunE:    runAdd,
 }
 
@@ -40,78 +52,356 @@ func init() {
 	addCmd.Flags().BoolVarP(&useExistingFlag, "use-existing", "e", false, "use existing branch if it exists")
 	addCmd.Flags().StringVar(&prFlag, "pr", "", "PR number, PR URL, or git remote URL with PR ref")
 	addCmd.Flags().StringVar(&issueFlag, "issue", "", "issue number, issue URL, or git remote URL with issue ref")
+	addCmd.Flags().StringVarP(&repoFlag, "repo", "R", "", "repository PR and issue numbers refer to, as [HOST/]OWNER/REPO (default: the current repository)")
+	addCmd.Flags().StringVar(&prAuthorFlag, "pr-author", "", "pick among open PRs by this author")
+	addCmd.Flags().StringVar(&prLabelFlag, "pr-label", "", "pick among open PRs with this label")
+	addCmd.MarkFlagsMutuallyExclusive("pr", "issue", "pr-author")
+	addCmd.MarkFlagsMutuallyExclusive("pr", "issue", "pr-label")
+	addCmd.Flags().IntVar(&limitFlag, "limit", defaultPRLimit, "maximum number of open PRs to pick from")
 	addCmd.Flags().StringVar(&actionFlag, "action", "", "action to run after worktree creation")
+	addCmd.Flags().BoolVarP(&openFlag, "open", "o", false, "open the worktree in your editor after creation")
+	addCmd.Flags().BoolVar(&webFlag, "web", false, "open the PR or issue in the browser after creation")
+	addCmd.Flags().BoolVar(&draftPRFlag, "draft-pr", false, "push issue branches and open a draft PR that closes the issue")
+	addCmd.Flags().BoolVar(&printPathFlag, "print-path", false, "print the worktree path as the last line of output")
+	addCmd.Flags().BoolVar(&tmuxFlag, "tmux", false, "open the worktree in a new tmux window when inside tmux")
+	addCmd.Flags().StringVar(&baseFlag, "base", "", "ref to start local and issue worktrees from (default HEAD, or the default branch for issues)")
+	addCmd.Flags().StringVar(&baseFlag, "from", "", "alias for --base")
+	_ = addCmd.Flags().MarkHidden("from")
+	addCmd.Flags().BoolVar(&noFetchFlag, "no-fetch", false, "use the previously fetched PR head instead of fetching")
+	addCmd.Flags().IntVar(&depthFlag, "depth", 0, "shallow fetch PRs with history truncated to this many commits")
+	addCmd.Flags().BoolVar(&trackFlag, "track", false, "make the new branch track its start point or remote branch")
+	addCmd.Flags().BoolVar(&noTrackFlag, "no-track", false, "do not set up upstream tracking for the new branch")
+	addCmd.MarkFlagsMutuallyExclusive("track", "no-track")
+	addCmd.Flags().BoolVar(&detachFlag, "detach", false, "check out the PR head or ref in a detached worktree without creating a branch")
+	addCmd.MarkFlagsMutuallyExclusive("detach", "track", "no-track")
+	addCmd.MarkFlagsMutuallyExclusive("detach", "draft-pr")
+	addCmd.Flags().BoolVar(&submodulesFlag, "recurse-submodules", false, "initialize and check out submodules in the new worktree")
+	addCmd.Flags().StringSliceVar(&sparseFlag, "sparse", nil, "limit the worktree to these directories with sparse-checkout (comma-separated or repeated)")
+	addCmd.Flags().StringVar(&pathFlag, "path", "", "create the worktree in this directory instead of under worktree_dir")
+	addCmd.Flags().StringVar(&nameFlag, "name", "", "name the local worktree's directory independently of its branch")
+	addCmd.MarkFlagsMutuallyExclusive("name", "path")
+	addCmd.MarkFlagsMutuallyExclusive("name", "pr", "issue", "pr-author")
+	addCmd.MarkFlagsMutuallyExclusive("name", "pr-label")
+	addCmd.Flags().StringVar(&shellFlag, "shell", "", "shell to quote the printed cd command for: bash, zsh, fish, or powershell (default from $SHELL)")
+	addCmd.Flags().StringVar(&remoteFlag, "remote", "", "remote to fetch from and track (default from default_remote, or origin)")
 	rootCmd.AddCommand(addCmd)
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
+	if cmd.Flags().Changed("depth") && depthFlag <= 0 {
+		return withExitCode(ExitUsage, fmt.Errorf("--depth must be a positive integer, got %d", depthFlag))
+	}
+	if limitFlag <= 0 {
+		return withExitCode(ExitUsage, fmt.Errorf("--limit must be a positive integer, got %d", limitFlag))
+	}
+	if shellFlag != "" {
+		if _, err := shell.Parse(shellFlag); err != nil {
+			return withExitCode(ExitUsage, fmt.Errorf("invalid --shell: %w", err))
+		}
+	}
+	if pathFlag != "" {
+		if len(args) > 1 {
+			return withExitCode(ExitUsage, fmt.Errorf("--path cannot be used with more than one worktree"))
+		}
+		// Resolve now, since creating a worktree may change into another directory first.
+		path, err := filepath.Abs(pathFlag)
+		if err != nil {
+			return fmt.Errorf("invalid path '%s': %w", pathFlag, err)
+		}
+		pathFlag = path
+	}
+	if nameFlag != "" {
+		if len(args) > 1 {
+			return withExitCode(ExitUsage, fmt.Errorf("--name cannot be used with more than one worktree"))
+		}
+		if err := validateWorktreeName(nameFlag); err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+	}
+	if remoteFlag != "" {
+		remotes, err := git.Remotes()
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(remotes, remoteFlag) {
+			return withExitCode(ExitNotFound, fmt.Errorf("remote '%s' not found; available remotes: %s", remoteFlag, strings.Join(remotes, ", ")))
+		}
+	}
+
 	// Determine the type of input
+	if prAuthorFlag != "" || prLabelFlag != "" {
+		if len(args) > 0 {
+			return withExitCode(ExitUsage, fmt.Errorf("--pr-author and --pr-label cannot be combined with arguments"))
+		}
+		number, err := pickPR(prAuthorFlag, prLabelFlag)
+		if err != nil || number == "" {
+			return err
+		}
+		return createFromPR(number)
+	}
 	if prFlag != "" {
-		return createFromPR(prFlag)
+		value, err := expandShorthand(prFlag, worktree.PR)
+		if err != nil {
+			return err
+		}
+		return createFromPR(value)
 	}
 	if issueFlag != "" {
-		return createFromIssue(issueFlag)
+		value, err := expandShorthand(issueFlag, worktree.Issue)
+		if err != nil {
+			return err
+		}
+		return createFromIssue(value)
 	}
 	if len(args) == 0 {
-		return cmd.Help()
+		// Offer a list of open PRs when running interactively inside a repository.
+		if !term.IsTerminal(os.Stdin) || !git.IsGitRepository(".") {
+			return cmd.Help()
+		}
+		number, err := pickPR("", "")
+		if err != nil || number == "" {
+			return err
+		}
+		return createFromPR(number)
 	}
 
-	// This is the main entry point for creating a worktree
-	arg := args[0]
-	worktreeType, err := DetermineWorktreeType(arg)
+	if len(args) == 1 {
+		return createFromArg(args[0])
+	}
+	return createFromArgs(args)
+}
+
+// createFromArgs creates a worktree for each argument, continuing past failures.
+func createFromArgs(args []string) error {
+	created, failed := 0, 0
+	for _, arg := range args {
+		Log.Outf(logger.Blue, "\n==> %s\n", arg)
+		if err := createFromArg(arg); err != nil {
+			Log.Errorf("Failed to create worktree for '%s': %v\n", arg, err)
+			failed++
+			continue
+		}
+		created++
+	}
+
+	Log.Outf(logger.Green, "\nCreated %d worktree(s), %d failed.\n", created, failed)
+	if failed > 0 {
+		return fmt.Errorf("failed to create %d worktree(s)", failed)
+	}
+	return nil
+}
+
+// createFromArg creates a worktree from a PR URL, issue URL, commit URL, or local name.
+// This is the main entry point for creating a worktree.
+func createFromArg(arg string) error {
+	arg, err := expandShorthand(arg, "")
+	if err != nil {
+		return err
+	}
+
+	worktreeType, err := ghwt.DetermineType(arg)
 	if err != nil {
 		return err
 	}
 
+	// PR and issue worktrees are named by worktree_name_template instead.
+	if nameFlag != "" && (worktreeType == worktree.PR || worktreeType == worktree.Issue) {
+		return withExitCode(ExitUsage, fmt.Errorf("--name can only be used for local worktrees"))
+	}
+
 	switch worktreeType {
 	case worktree.PR:
 		return createFromPR(arg)
 	case worktree.Issue:
 		return createFromIssue(arg)
+	case ghwt.Commit:
+		return createFromCommit(arg)
 	default:
 		return createFromLocal(arg)
 	}
 }
 
+// defaultPRLimit is the number of open PRs the picker lists unless --limit is given.
+const defaultPRLimit = 30
+
+// pickPR lets the user select one of the repository's open PRs, optionally only those by
+// author or with label. At most --limit PRs are listed; typing in the prompt filters them.
+// A filtered list with a single PR needs no prompt.
+// Returns an empty string if there are no matching open PRs.
+func pickPR(author, label string) (string, error) {
+	args := []string{"pr", "list", "--json", "number,title,headRefName,author", "--limit", strconv.Itoa(limitFlag)}
+	if author != "" {
+		args = append(args, "--author", author)
+	}
+	if label != "" {
+		args = append(args, "--label", label)
+	}
+	if repoFlag != "" {
+		args = append(args, "--repo", repoFlag)
+	}
+	stdout, stderr, err := ghExec(args...)
+	if err != nil {
+		return "", ghError("failed to list PRs", err, stderr)
+	}
+
+	var prs []struct {
+		Number      int    `json:"number"`
+		Title       string `json:"title"`
+		HeadRefName string `json:"headRefName"`
+		Author      struct {
+			Login string `json:"login"`
+		} `json:"author"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &prs); err != nil {
+		return "", fmt.Errorf("failed to parse PR list: %w", err)
+	}
+
+	filtered := author != "" || label != ""
+	switch {
+	case len(prs) == 0 && filtered:
+		Log.Warnf("No open pull requests match.\n")
+		return "", nil
+	case len(prs) == 0:
+		Log.Warnf("No open pull requests found.\n")
+		return "", nil
+	case len(prs) == 1 && filtered:
+		Log.Infof("Using PR #%d %s\n", prs[0].Number, prs[0].Title)
+		return strconv.Itoa(prs[0].Number), nil
+	case !term.IsTerminal(os.Stdin):
+		return "", fmt.Errorf("%d open pull requests match; use --pr to choose one", len(prs))
+	}
+
+	if len(prs) == limitFlag {
+		Log.Infof("Showing the first %d open pull requests; use --limit to list more.\n", limitFlag)
+	}
+
+	options := make([]string, len(prs))
+	for i, pr := range prs {
+		options[i] = fmt.Sprintf("#%d %s (%s, by %s)", pr.Number, pr.Title, pr.HeadRefName, pr.Author.Login)
+	}
+
+	p := newAddPrompter()
+	idx, err := p.Select("Select a pull request (type to filter):", "", options)
+	if err != nil {
+		return "", fmt.Errorf("prompt failed: %w", err)
+	}
+	return strconv.Itoa(prs[idx].Number), nil
+}
+
 // createFromPR handles creation from a PR URL or number.
 func createFromPR(value string) error {
+	value, err := qualifyNumber(value, worktree.PR)
+	if err != nil {
+		return err
+	}
+	value = normalizeRef(value)
 	Log.Infof("Fetching Pull Request info...\n")
-	args := []string{"pr", "view", value, "--json", "number,title,headRefName,url"}
-	stdout, stderr, err := gh.Exec(args...)
+	args := []string{"pr", "view", value, "--json", "number,title,headRefName,headRefOid,url,isCrossRepository,headRepositoryOwner,headRepository,baseRefName,author,isDraft"}
+	stdout, stderr, err := ghExec(append(args, repoArgs(value)...)...)
 	if err != nil {
-		return fmt.Errorf("failed to fetch PR info: %s\n%s", err, stderr.String())
+		return ghError("failed to fetch PR info", err, stderr)
 	}
 
 	var prInfo struct {
 		Number      int    `json:"number"`
 		Title       string `json:"title"`
 		HeadRefName string `json:"headRefName"`
+		HeadRefOid  string `json:"headRefOid"`
 		URL         string `json:"url"`
+		BaseRefName string `json:"baseRefName"`
+		IsDraft     bool   `json:"isDraft"`
+		Author      struct {
+			Login string `json:"login"`
+		} `json:"author"`
+
+		IsCrossRepository   bool `json:"isCrossRepository"`
+		HeadRepositoryOwner struct {
+			Login string `json:"login"`
+		} `json:"headRepositoryOwner"`
+		HeadRepository struct {
+			Name string `json:"name"`
+		} `json:"headRepository"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &prInfo); err != nil {
 		return fmt.Errorf("failed to parse PR info: %w", err)
 	}
 
-	repo, err := repository.Current()
-	if err != nil {
+	// The URL gh returns names the repository, so this works for PR URLs outside a clone as well.
+	repo, ok := ghwt.ParseURL(prInfo.URL)
+	if !ok {
+		return fmt.Errorf("unexpected PR URL from gh: %q", prInfo.URL)
+	}
+	if err := ensureLocalRepo(repo); err != nil {
 		return err
 	}
+	remote := remoteName()
 
 	info := &worktree.WorktreeInfo{
 		Type:         worktree.PR,
 		Owner:        repo.Owner,
-		Repo:         repo.Name,
+		Repo:         repo.Repo,
 		Number:       prInfo.Number,
-		BranchName:   prInfo.HeadRefName,
+		BranchName:   prefixBranch(worktree.PR, prInfo.HeadRefName),
 		WorktreeName: fmt.Sprintf("pr_%d", prInfo.Number),
+		URL:          prInfo.URL,
+
+		Author:     prInfo.Author.Login,
+		BaseBranch: prInfo.BaseRefName,
+		HeadOwner:  prInfo.HeadRepositoryOwner.Login,
+		Draft:      prInfo.IsDraft,
+
+		UpstreamRemote: remote,
+		UpstreamBranch: prInfo.HeadRefName,
+	}
+
+	// Fork branches often share generic names like "patch-1", so name them after the PR and fork owner.
+	if prInfo.IsCrossRepository {
+		forkOwner := prInfo.HeadRepositoryOwner.Login
+		info.BranchName = prefixBranch(worktree.PR, SanitizeBranchName(fmt.Sprintf("pr_%d_%s", prInfo.Number, forkOwner)))
+		info.UpstreamRemote = fmt.Sprintf("https://%s/%s/%s.git", repo.Host, forkOwner, prInfo.HeadRepository.Name)
+		Log.Infof("PR #%d is from fork %s/%s (branch '%s')\n", prInfo.Number, forkOwner, prInfo.HeadRepository.Name, prInfo.HeadRefName)
 	}
 
 	Log.Outf(logger.Green, "Creating worktree for PR #%d: %s\n", info.Number, prInfo.Title)
 
-	// Fetch the PR ref
+	cfg, err := config.Get()
+	if err != nil {
+		return err
+	}
+	if cfg.PRBackend == config.PRBackendGh {
+		if noFetchFlag || depthFlag > 0 {
+			Log.Warnf("⚠️  Ignoring --no-fetch and --depth: gh pr checkout always fetches the PR\n")
+		}
+		// Start detached so that gh pr checkout can create the branch itself, with gh's tracking setup.
+		return createWorktreeWith(info, ghwt.CreateOptions{
+			StartPoint: "HEAD",
+			Detach:     true,
+			Checkout:   ghPRCheckout(prInfo.URL, info.BranchName),
+		})
+	}
+
+	// Fetched PR heads are kept under a private ref so that --no-fetch can reuse them.
 	prRef := fmt.Sprintf("refs/pull/%d/head", info.Number)
+	cachedRef := fmt.Sprintf("refs/gh-wt/pull/%d/head", info.Number)
+
+	if noFetchFlag {
+		if err := git.VerifyRef(cachedRef); err != nil {
+			return fmt.Errorf("PR #%d has not been fetched yet; run again without --no-fetch", info.Number)
+		}
+		Log.Infof("Using previously fetched PR #%d\n", info.Number)
+		return createWorktree(info, cachedRef)
+	}
+
+	// A previous fetch that already has the PR's current head makes fetching again unnecessary.
+	if sha, err := git.CommitSHA(cachedRef); err == nil && prInfo.HeadRefOid != "" && sha == prInfo.HeadRefOid {
+		Log.Infof("PR #%d is up to date, skipping fetch\n", info.Number)
+		return createWorktree(info, cachedRef)
+	}
+
 	Log.Infof("Fetching PR #%d...\n", info.Number)
-	if err := git.Fetch(prRef); err != nil {
+	ctx, cancel := gitContext()
+	defer cancel()
+	if err := git.FetchDepth(ctx, remote, depthFlag, "+"+prRef+":"+cachedRef); err != nil {
 		return fmt.Errorf("failed to fetch PR: %w", err)
 	}
 
@@ -120,39 +410,190 @@ func createFromPR(value string) error {
 
 // createFromIssue handles creation from an Issue URL or number.
 func createFromIssue(value string) error {
+	value, err := qualifyNumber(value, worktree.Issue)
+	if err != nil {
+		return err
+	}
+	value = normalizeRef(value)
 	Log.Infof("Fetching Issue info...\n")
-	args := []string{"issue", "view", value, "--json", "number,title,url"}
-	stdout, stderr, err := gh.Exec(args...)
+	args := []string{"issue", "view", value, "--json", "number,title,url,author"}
+	stdout, stderr, err := ghExec(append(args, repoArgs(value)...)...)
 	if err != nil {
-		return fmt.Errorf("failed to fetch Issue info: %s\n%s", err, stderr.String())
+		return ghError("failed to fetch Issue info", err, stderr)
 	}
 
 	var issueInfo struct {
 		Number int    `json:"number"`
 		Title  string `json:"title"`
 		URL    string `json:"url"`
+		Author struct {
+			Login string `json:"login"`
+		} `json:"author"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &issueInfo); err != nil {
 		return fmt.Errorf("failed to parse issue info: %w", err)
 	}
 
-	repo, err := repository.Current()
+	repo, ok := ghwt.ParseURL(issueInfo.URL)
+	if !ok {
+		return fmt.Errorf("unexpected issue URL from gh: %q", issueInfo.URL)
+	}
+	if err := ensureLocalRepo(repo); err != nil {
+		return err
+	}
+
+	startPoint, err := resolveStartPoint()
 	if err != nil {
 		return err
 	}
+	cfg, err := config.Get()
+	if err != nil {
+		return err
+	}
+	if baseFlag == "" && !cfg.IssueFromHead {
+		startPoint = issueStartPoint()
+	}
 
-	branchName := fmt.Sprintf("issue_%d", issueInfo.Number)
+	name := fmt.Sprintf("issue_%d", issueInfo.Number)
 	info := &worktree.WorktreeInfo{
 		Type:         worktree.Issue,
 		Owner:        repo.Owner,
-		Repo:         repo.Name,
+		Repo:         repo.Repo,
 		Number:       issueInfo.Number,
-		BranchName:   branchName,
-		WorktreeName: branchName,
+		BranchName:   prefixBranch(worktree.Issue, name),
+		WorktreeName: name,
+		URL:          issueInfo.URL,
+		Author:       issueInfo.Author.Login,
+	}
+
+	linked, err := useLinkedBranch(info, issueInfo.URL)
+	if err != nil {
+		return err
+	}
+	if linked {
+		startPoint = "refs/remotes/" + info.UpstreamRemote + "/" + info.BranchName
 	}
 
 	Log.Outf(logger.Green, "Creating worktree for Issue #%d: %s\n", info.Number, issueInfo.Title)
-	return createWorktree(info, "HEAD") // Issues start from HEAD
+	if err := createWorktree(info, startPoint); err != nil {
+		return err
+	}
+
+	if draftPRFlag {
+		if linked {
+			Log.Warnf("⚠️  Skipping draft PR: linked branch '%s' already exists on %s\n", info.BranchName, info.UpstreamRemote)
+			return nil
+		}
+		createDraftPR(info, issueInfo.Title, startPoint)
+	}
+	return nil
+}
+
+// useLinkedBranch offers to check out a development branch linked to the issue at issueURL on GitHub
+// instead of creating a new one. When chosen, the branch is fetched and info is updated to track it.
+// The offer is only made interactively; otherwise a new branch is created as before.
+func useLinkedBranch(info *worktree.WorktreeInfo, issueURL string) (bool, error) {
+	if forceFlag || !term.IsTerminal(os.Stdin) {
+		return false, nil
+	}
+
+	stdout, stderr, err := ghExec("issue", "develop", "--list", issueURL)
+	if err != nil {
+		Log.Debugf("Could not list linked branches: %v\n", ghError("gh issue develop", err, stderr))
+		return false, nil
+	}
+
+	// Each line is "<branch>\t<url>".
+	var branches []string
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			branches = append(branches, fields[0])
+		}
+	}
+	if len(branches) == 0 {
+		return false, nil
+	}
+
+	options := make([]string, 0, len(branches)+1)
+	for _, branch := range branches {
+		options = append(options, fmt.Sprintf("Use linked branch '%s'", branch))
+	}
+	options = append(options, fmt.Sprintf("Create new branch '%s'", info.BranchName))
+
+	p := newAddPrompter()
+	idx, err := p.Select(fmt.Sprintf("Issue #%d has linked branches:", info.Number), options[0], options)
+	if err != nil {
+		return false, fmt.Errorf("prompt failed: %w", err)
+	}
+	if idx == len(branches) {
+		return false, nil
+	}
+
+	branch := branches[idx]
+	remote := remoteName()
+	Log.Infof("Fetching branch '%s' from %s...\n", branch, remote)
+	ctx, cancel := gitContext()
+	defer cancel()
+	if err := git.Fetch(ctx, remote, fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", branch, remote, branch)); err != nil {
+		return false, fmt.Errorf("failed to fetch linked branch: %w", err)
+	}
+
+	info.BranchName = branch
+	info.UpstreamRemote = remote
+	info.UpstreamBranch = branch
+	return true, nil
+}
+
+// createDraftPR pushes the issue branch and opens a draft PR that closes the issue.
+// The branch gets an empty commit first if it has none, since GitHub rejects PRs without commits.
+// Failures are reported as warnings since the worktree itself was created successfully.
+func createDraftPR(info *worktree.WorktreeInfo, title, startPoint string) {
+	remote := remoteName()
+	remotes, err := git.Remotes()
+	if err != nil || !slices.Contains(remotes, remote) {
+		Log.Warnf("⚠️  Skipping draft PR: no '%s' remote to push to\n", remote)
+		return
+	}
+
+	// createWorktree returns without error when the user cancels, so look up the worktree.
+	var worktreePath string
+	if worktrees, err := git.GetWorktreeInfo(); err == nil {
+		for _, wt := range worktrees {
+			if wt.Branch == info.BranchName {
+				worktreePath = wt.Path
+				break
+			}
+		}
+	}
+	if worktreePath == "" {
+		return
+	}
+
+	if ahead, err := git.CommitsAhead(worktreePath, startPoint); err == nil && ahead == 0 {
+		if err := git.CommitEmpty(worktreePath, fmt.Sprintf("Start work on #%d", info.Number)); err != nil {
+			Log.Warnf("⚠️  Skipping draft PR: failed to create initial commit: %v\n", err)
+			return
+		}
+	}
+
+	Log.Infof("Pushing branch '%s'...\n", info.BranchName)
+	ctx, cancel := gitContext()
+	defer cancel()
+	if err := git.PushUpstream(ctx, remote, info.BranchName); err != nil {
+		Log.Warnf("⚠️  Skipping draft PR: failed to push branch: %v\n", err)
+		return
+	}
+
+	Log.Infof("Creating draft PR...\n")
+	stdout, stderr, err := ghExec("pr", "create", "--draft",
+		"--head", info.BranchName,
+		"--title", title,
+		"--body", fmt.Sprintf("Closes #%d", info.Number))
+	if err != nil {
+		Log.Warnf("⚠️  %v\n", ghError("failed to create draft PR", err, stderr))
+		return
+	}
+	Log.Outf(logger.Green, "Draft PR created: %s\n", strings.TrimSpace(stdout.String()))
 }
 
 // createFromLocal handles creation from a local branch name.
@@ -161,6 +602,11 @@ func createFromLocal(name string) error {
 		return fmt.Errorf("not in a git repository")
 	}
 
+	startPoint, err := resolveStartPoint()
+	if err != nil {
+		return err
+	}
+
 	// Get repo name using the shared helper
 	repoName, err := git.GetRepoName()
 	if err != nil {
@@ -170,29 +616,287 @@ func createFromLocal(name string) error {
 	// Sanitize the name for the branch
 	sanitizedBranchName := SanitizeBranchName(name)
 
+	// --name decouples the worktree directory from the branch, e.g. "login" for "feature/login".
+	worktreeName := nameFlag
+
+	// A detached worktree of an existing ref, such as a local branch, checks out that ref itself.
+	if detachFlag && baseFlag == "" && git.VerifyRef(name) == nil {
+		startPoint = name
+	}
+
+	// A remote branch such as origin/feature-x (or just feature-x) gets a local branch tracking it.
+	if baseFlag == "" && !git.BranchExists(sanitizedBranchName) {
+		if remote, branch, ok := matchRemoteBranch(name); ok {
+			Log.Infof("Using remote branch '%s/%s'\n", remote, branch)
+			info := &worktree.WorktreeInfo{
+				Type:           worktree.Local,
+				Repo:           repoName,
+				BranchName:     branch,
+				WorktreeName:   cmp.Or(worktreeName, SanitizeWorktreeName(branch)),
+				UpstreamRemote: remote,
+				UpstreamBranch: branch,
+			}
+			return createWorktree(info, "refs/remotes/"+remote+"/"+branch)
+		}
+	}
+
+	// A tag or commit gets a new branch starting at it, named after the tag or sha_<short SHA>.
+	if baseFlag == "" {
+		switch git.ResolveRef(name) {
+		case git.RefTag:
+			startPoint = "refs/tags/" + name
+		case git.RefCommit:
+			startPoint = name
+			short, err := git.ShortSHA(name)
+			if err != nil {
+				return err
+			}
+			// Prefixed like pr_<n> so the branch name is not ambiguous with the SHA itself.
+			sanitizedBranchName = "sha_" + short
+			name = sanitizedBranchName
+		}
+	}
+
+	// Existing branches are checked out under their own name.
+	if !git.BranchExists(sanitizedBranchName) {
+		sanitizedBranchName = prefixBranch(worktree.Local, sanitizedBranchName)
+	}
+
 	info := &worktree.WorktreeInfo{
 		Type:         worktree.Local,
 		Repo:         repoName,
 		BranchName:   sanitizedBranchName,
-		WorktreeName: name, // Worktree directory keeps the original name
+		WorktreeName: cmp.Or(worktreeName, SanitizeWorktreeName(name)),
+	}
+
+	return createWorktree(info, startPoint)
+}
+
+// createFromCommit handles creation from a commit URL. The commit is fetched from the remote
+// when it is not in the repository yet, and is then checked out like a SHA given by itself.
+func createFromCommit(value string) error {
+	if baseFlag != "" {
+		return withExitCode(ExitUsage, fmt.Errorf("--base cannot be used with a commit URL"))
+	}
+	repo, sha, _ := ghwt.ParseCommitURL(value)
+	if err := ensureLocalRepo(repo); err != nil {
+		return err
+	}
+
+	if git.VerifyRef(sha) != nil {
+		remote := remoteName()
+		Log.Infof("Fetching commit %s from %s...\n", sha, remote)
+		ctx, cancel := gitContext()
+		defer cancel()
+		if err := git.Fetch(ctx, remote, sha); err != nil {
+			return withExitCode(ExitNotFound, fmt.Errorf("commit %s not found in %s: %w", sha, remote, err))
+		}
+	}
+	return createFromLocal(sha)
+}
+
+// checkedOutElsewhere returns the path of the worktree other than path that has branch checked
+// out, or an empty string if there is none.
+func checkedOutElsewhere(branch, path string) string {
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		return ""
+	}
+	for _, wt := range worktrees {
+		if wt.Branch == branch && filepath.Clean(wt.Path) != filepath.Clean(path) {
+			return wt.Path
+		}
+	}
+	return ""
+}
+
+// ghPRCheckout returns a ghwt.CreateOptions.Checkout step that runs gh pr checkout for the PR at
+// url in the new worktree, on branch or, with --detach, without one.
+func ghPRCheckout(url, branch string) func(context.Context, string) error {
+	return func(ctx context.Context, path string) error {
+		args := []string{"pr", "checkout", url}
+		if detachFlag {
+			args = append(args, "--detach")
+		} else {
+			args = append(args, "--branch", branch)
+		}
+		Log.Infof("Checking out PR with gh pr checkout...\n")
+		if _, stderr, err := ghExecIn(ctx, path, args...); err != nil {
+			return ghError("failed to check out PR", err, stderr)
+		}
+		return nil
+	}
+}
+
+// matchRemoteBranch finds the remote-tracking branch name refers to, either as
+// <remote>/<branch> or as a branch on the default remote. Only already fetched branches are found.
+func matchRemoteBranch(name string) (remote, branch string, ok bool) {
+	remotes, err := git.Remotes()
+	if err != nil {
+		return "", "", false
+	}
+	for _, r := range remotes {
+		if b, found := strings.CutPrefix(name, r+"/"); found && git.RemoteBranchExists(r, b) {
+			return r, b, true
+		}
+	}
+	if remote := remoteName(); slices.Contains(remotes, remote) && git.RemoteBranchExists(remote, name) {
+		return remote, name, true
 	}
+	return "", "", false
+}
 
-	return createWorktree(info, "HEAD")
+// resolveStartPoint returns the ref new local and issue branches start from.
+// It defaults to HEAD and validates the --base ref when one is given.
+func resolveStartPoint() (string, error) {
+	if baseFlag == "" {
+		return "HEAD", nil
+	}
+	if err := git.VerifyRef(baseFlag); err != nil {
+		return "", withExitCode(ExitNotFound, fmt.Errorf("base ref '%s' not found; check the name or fetch it first (e.g. git fetch %s %s)", baseFlag, remoteName(), baseFlag))
+	}
+	return baseFlag, nil
+}
+
+// issueStartPoint returns the default branch of the default remote that issue branches start from.
+// It falls back to gh for the branch name and to HEAD if it cannot be determined.
+func issueStartPoint() string {
+	remote := remoteName()
+	if ref, err := git.DefaultBranch(remote); err == nil {
+		return ref
+	}
+
+	stdout, _, err := ghExec("repo", "view", "--json", "defaultBranchRef", "--jq", ".defaultBranchRef.name")
+	if branch := strings.TrimSpace(stdout.String()); err == nil && branch != "" && git.RemoteBranchExists(remote, branch) {
+		return remote + "/" + branch
+	}
+
+	Log.Warnf("⚠️  Could not determine the default branch, starting from HEAD\n")
+	return "HEAD"
+}
+
+// prefixBranch prepends the configured branch prefix for worktrees of type t to branch,
+// unless branch already starts with it.
+func prefixBranch(t worktree.WorktreeType, branch string) string {
+	cfg, err := config.Get()
+	if err != nil {
+		return branch
+	}
+	prefix := map[worktree.WorktreeType]string{
+		worktree.PR:    cfg.BranchPrefixPR,
+		worktree.Issue: cfg.BranchPrefixIssue,
+		worktree.Local: cfg.BranchPrefixLocal,
+	}[t]
+	if strings.HasPrefix(branch, prefix) {
+		return branch
+	}
+	return prefix + branch
+}
+
+// remoteName returns the remote to fetch from and track: --remote, then default_remote, then origin.
+func remoteName() string {
+	if remoteFlag != "" {
+		return remoteFlag
+	}
+	if cfg, err := config.Get(); err == nil && cfg.DefaultRemote != "" {
+		return cfg.DefaultRemote
+	}
+	return config.DefaultRemote
 }
 
 // createWorktree is the central function that performs the creation.
 // It contains all the logic for path generation, user prompts, and calling the worktree package.
 func createWorktree(info *worktree.WorktreeInfo, startPoint string) error {
+	return createWorktreeWith(info, ghwt.CreateOptions{StartPoint: startPoint})
+}
+
+// createWorktreeWith is createWorktree with opts as the starting point for the options passed to
+// ghwt.Create; the path, tracking, and sparse paths are filled in from the flags and config.
+func createWorktreeWith(info *worktree.WorktreeInfo, opts ghwt.CreateOptions) error {
+	startPoint := opts.StartPoint
 	cfg, err := config.Get()
 	if err != nil {
 		return err
 	}
-	baseDir := cfg.WorktreeBase
-	worktreePath := filepath.Join(baseDir, info.Repo, info.WorktreeName)
+	baseDir, pathTemplate := worktreeLayout(cfg)
+	layout := ghwt.Layout{BaseDir: baseDir, PathTemplate: pathTemplate, NameTemplate: cfg.WorktreeNameTemplate}
+
+	if err := git.RequireVersion(git.MinVersion, "gh wt"); err != nil {
+		Log.Warnf("⚠️  %v; some features may not work\n", err)
+	}
+
+	// ghwt.Create checks the branch name as well, but only after the prompts below.
+	if !detachFlag && !opts.Detach {
+		if err := ghwt.ValidateBranchName(info.BranchName); err != nil {
+			return err
+		}
+	}
+
+	// PR and issue worktree names can be customized, local names come from the user.
+	// --path bypasses the layout and names the worktree after its directory.
+	var worktreePath string
+	if pathFlag != "" {
+		worktreePath = pathFlag
+		info.WorktreeName = filepath.Base(worktreePath)
+		if err := checkNotInsideWorktree(worktreePath); err != nil {
+			return err
+		}
+	} else if worktreePath, err = ghwt.Path(layout, info); err != nil {
+		return err
+	}
+
+	// Re-running add for a PR that already has its worktree can simply reuse it.
+	if info.Type == worktree.PR && !forceFlag {
+		reused, err := reuseExistingWorktree(worktreePath, info, cfg.Editor)
+		if err != nil || reused {
+			return err
+		}
+	}
+
+	if worktree.Exists(worktreePath) || git.WorktreeIsRegistered(worktreePath) {
+		switch cfg.OnNameCollision {
+		case config.CollisionError:
+			return withExitCode(ExitConflict, fmt.Errorf("worktree directory already exists: %s", worktreePath))
+		case config.CollisionSuffix:
+			// An explicit --path is never changed; the conflict is resolved below instead.
+			if pathFlag != "" {
+				break
+			}
+			worktreePath, err = ghwt.NextFreePath(layout, info)
+			if err != nil {
+				return err
+			}
+			Log.Infof("Worktree name taken, using '%s' instead\n", info.WorktreeName)
+		}
+	}
 	absPath, _ := filepath.Abs(worktreePath)
 
 	// Check conditions
-	branchExists := git.BranchExists(info.BranchName)
+	// Detached worktrees create no branch, so an existing branch is no conflict.
+	branchExists := !detachFlag && git.BranchExists(info.BranchName)
+
+	// git checks a branch out in only one worktree and refuses to delete it while it is,
+	// so neither attaching to nor overwriting a branch checked out elsewhere can work.
+	if branchExists {
+		if other := checkedOutElsewhere(info.BranchName, absPath); other != "" {
+			hint := "use --detach to check out its commit without a branch"
+			if info.Type == worktree.Local {
+				hint += fmt.Sprintf(", or start a new branch from it with 'gh wt add <new-name> --base %s'", info.BranchName)
+			}
+			return withExitCode(ExitConflict, fmt.Errorf("branch '%s' is already checked out in the worktree at %s; %s", info.BranchName, other, hint))
+		}
+	}
+
+	// --use-existing, or default_existing_action: attach, checks out an existing branch as is.
+	existingAction := cfg.DefaultExistingAction
+	if useExistingFlag {
+		existingAction = config.ExistingAttach
+	}
+	if branchExists && existingAction == config.ExistingAttach {
+		Log.Infof("Using existing branch '%s'\n", info.BranchName)
+		startPoint, opts.StartPoint = "", ""
+		branchExists = false
+	}
 	worktreeDirExists := worktree.Exists(worktreePath)
 	worktreeGitRegistered := git.WorktreeIsRegistered(worktreePath)
 
@@ -200,8 +904,6 @@ func createWorktree(info *worktree.WorktreeInfo, startPoint string) error {
 	hasConflict := worktreeDirExists || worktreeGitRegistered || branchExists
 
 	if hasConflict {
-		p := prompter.New(os.Stdin, os.Stdout, os.Stderr)
-
 		// Build the "This will:" message
 		var message strings.Builder
 		message.WriteString("Target: create worktree for '")
@@ -248,8 +950,13 @@ func createWorktree(info *worktree.WorktreeInfo, startPoint string) error {
 		}
 
 		// Add create action
-		message.WriteString("- Create worktree and branch for '")
-		message.WriteString(info.BranchName)
+		if detachFlag {
+			message.WriteString("- Create detached worktree at '")
+			message.WriteString(startPoint)
+		} else {
+			message.WriteString("- Create worktree and branch for '")
+			message.WriteString(info.BranchName)
+		}
 		message.WriteString("'\n")
 
 		// Check worktree for uncommitted changes
@@ -279,59 +986,180 @@ func createWorktree(info *worktree.WorktreeInfo, startPoint string) error {
 			}
 		}
 
-		message.WriteString("\nOverwrite?")
-
-		// If force flag is set, skip the prompt
-		if !forceFlag {
-			overwrite, err := p.Confirm(message.String(), false)
-			if err != nil {
-				return fmt.Errorf("failed to read confirmation: %w", err)
-			}
-			if !overwrite {
-				Log.Warnf("Cancelled - no changes made\n")
-				return nil
+		// List the commits that only exist on the branch and would be lost with it.
+		unmerged := 0
+		if branchExists {
+			var commits []string
+			unmerged, commits, _ = git.UnmergedCommits(info.BranchName, 5)
+			if unmerged > 0 {
+				fmt.Fprintf(&message, "\n⚠️  DESTRUCTIVE: Branch '%s' has %d commit(s) that are not merged into HEAD or pushed. They will be lost:\n", info.BranchName, unmerged)
+				for _, commit := range commits {
+					message.WriteString("    ")
+					message.WriteString(commit)
+					message.WriteString("\n")
+				}
+				if unmerged > len(commits) {
+					fmt.Fprintf(&message, "    ... and %d more\n", unmerged-len(commits))
+				}
 			}
 		}
 
-		// Perform cleanup based on what exists
-		if worktreeDirExists && worktreeGitRegistered {
-			// Valid worktree - use git to remove
-			if err := git.WorktreeRemove(worktreePath, true); err != nil {
-				return fmt.Errorf("failed to remove worktree: %w", err)
-			}
-		} else if worktreeDirExists {
-			// Disk only - just remove directory
-			if err := os.RemoveAll(worktreePath); err != nil {
-				return fmt.Errorf("failed to remove directory: %w", err)
+		// If force flag is set, skip the prompt and overwrite.
+		// default_existing_action: overwrite skips it as well when only the branch exists.
+		autoOverwrite := existingAction == config.ExistingOverwrite && !worktreeDirExists && !worktreeGitRegistered
+		if !forceFlag {
+			p := newAddPrompter()
+			switch {
+			case autoOverwrite && unmerged == 0:
+				Log.Infof("Overwriting existing branch '%s'\n", info.BranchName)
+			case !stdinIsTerminal():
+				return withExitCode(ExitConflict, fmt.Errorf("worktree or branch for '%s' already exists; use --force to overwrite it", info.BranchName))
+			case !autoOverwrite:
+				overwrite, err := p.Confirm(message.String()+"\nOverwrite?", false)
+				if err != nil {
+					return fmt.Errorf("failed to read confirmation: %w", err)
+				}
+				if !overwrite {
+					Log.Warnf("Cancelled - no changes made\n")
+					return nil
+				}
 			}
-		} else if worktreeGitRegistered {
-			// Git only - prune the record
-			if err := git.WorktreePrune(); err != nil {
-				return fmt.Errorf("failed to prune worktree: %w", err)
+
+			// Losing commits needs a second, explicit confirmation.
+			if unmerged > 0 {
+				if autoOverwrite {
+					Log.Errf(logger.Default, "%s\n", message.String())
+				}
+				confirmed, err := p.Confirm(fmt.Sprintf("Really delete %d unmerged commit(s) on '%s'?", unmerged, info.BranchName), false)
+				if err != nil {
+					return fmt.Errorf("failed to read confirmation: %w", err)
+				}
+				if !confirmed {
+					Log.Warnf("Cancelled - no changes made\n")
+					return nil
+				}
 			}
 		}
 
-		// Delete branch if it exists
+		// ghwt.Create removes the existing worktree and branch before creating the new ones.
+		opts.Overwrite = worktreeDirExists || worktreeGitRegistered
+		opts.OverwriteBranch = branchExists
 		if branchExists {
 			Log.Infof("Deleting existing branch '%s'...\n", info.BranchName)
-			if err := git.BranchDelete(info.BranchName, true); err != nil {
-				return fmt.Errorf("failed to delete branch: %w", err)
-			}
 		}
 	}
 
 	// Create the new worktree.
-	err = worktree.Create(worktreePath, info.BranchName, startPoint)
-	if err != nil {
-		// Simple cleanup: if creation fails, try to remove the directory if it was created.
-		if worktree.Exists(worktreePath) {
-			os.RemoveAll(worktreePath)
+	ctx, cancel := gitContext()
+	defer cancel()
+
+	if cfg.FetchPrune && !noFetchFlag {
+		remote := remoteName()
+		Log.Infof("Pruning stale remote branches of %s...\n", remote)
+		if err := git.FetchWithPrune(ctx, remote); err != nil {
+			Log.Warnf("⚠️  Failed to prune remote branches: %v\n", err)
 		}
+	}
+
+	// PR and remote branch worktrees track their remote branch by default, other branches follow git's defaults.
+	// --track makes those track their start point instead, which git requires to be a branch.
+	opts.Path = absPath
+	opts.Detach = opts.Detach || detachFlag
+	switch {
+	case noTrackFlag:
+		opts.Track = git.TrackNever
+	case trackFlag && info.UpstreamRemote == "":
+		opts.Track = git.TrackAlways
+	}
+
+	opts.SparsePaths = cfg.DefaultSparsePaths
+	if len(sparseFlag) > 0 {
+		opts.SparsePaths = sparseFlag
+	}
+	if len(opts.SparsePaths) > 0 {
+		Log.Infof("Limiting checkout to %s...\n", strings.Join(opts.SparsePaths, ", "))
+	}
+
+	result, err := ghwt.Create(ctx, info, opts)
+	if err != nil {
 		return err
 	}
+	recordRecent(absPath)
+	for _, warning := range result.Warnings {
+		Log.Warnf("⚠️  %v\n", warning)
+	}
+
+	if submodulesFlag || cfg.RecurseSubmodules {
+		updateSubmodules(ctx, absPath)
+	}
+
+	if len(cfg.CopyFiles) > 0 {
+		copyUntrackedFiles(cfg.CopyFiles, cfg.CopyRespectGitignore, absPath)
+	}
+
+	if len(cfg.LinkDirs) > 0 {
+		linkSharedDirs(cfg.LinkDirs, absPath)
+	}
+
+	if cfg.DirenvAllow {
+		allowDirenv(absPath)
+	}
+
+	if (tmuxFlag || cfg.OpenInTmux) && tmux.InSession() {
+		openInTmux(filepath.Base(absPath), absPath)
+	} else {
+		printSuccess(absPath)
+	}
+
+	if cfg.PostCreateHook != "" {
+		runPostCreateHook(cfg.PostCreateHook, absPath, info)
+	}
+
+	if openFlag {
+		openInEditor(cfg.Editor, absPath)
+	}
+
+	if webFlag {
+		openInBrowser(info)
+	}
 
-	printSuccess(absPath)
+	runActionOrArgs(info, absPath)
+
+	printPath(absPath)
+	return nil
+}
 
+// checkNotInsideWorktree returns an error if path lies inside an existing worktree, including the
+// main worktree. path itself may be an existing worktree, which is handled like a name collision.
+func checkNotInsideWorktree(path string) error {
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		return err
+	}
+	owner := containingWorktree(worktrees, path)
+	if owner != "" && resolvePath(owner) != resolvePath(path) {
+		return fmt.Errorf("path '%s' is inside worktree '%s'", path, owner)
+	}
+	return nil
+}
+
+// newAddPrompter returns a prompter that writes to stderr, so that prompts stay visible and
+// command substitution such as cd "$(gh wt add name --print-path)" only captures the path.
+func newAddPrompter() *prompter.Prompter {
+	return prompter.New(os.Stdin, os.Stderr, os.Stderr)
+}
+
+// printPath prints the bare worktree path as the last line for --print-path.
+// In quiet mode the path has already been printed as the only output.
+func printPath(path string) {
+	if printPathFlag && !Log.Quiet() {
+		Log.Plainf("%s\n", path)
+	}
+}
+
+// runActionOrArgs runs the --action, or the CLI args after -- if no action is given, in the worktree.
+// Failures are reported as warnings since the worktree itself exists.
+func runActionOrArgs(info *worktree.WorktreeInfo, absPath string) {
 	if actionFlag != "" {
 		if err := action.Execute(context.Background(), &action.ExecuteOptions{
 			ActionName:   actionFlag,
@@ -362,56 +1190,360 @@ func createWorktree(info *worktree.WorktreeInfo, startPoint string) error {
 			Log.Warnf("\n⚠️  Command '%s' failed: %v\n", cliArgs, err)
 		}
 	}
+}
 
-	return nil
+// reuseExistingWorktree offers to use the worktree at worktreePath if it is already
+// checked out on the PR branch. It reports whether the existing worktree was used.
+func reuseExistingWorktree(worktreePath string, info *worktree.WorktreeInfo, editorCmd string) (bool, error) {
+	if !worktree.Exists(worktreePath) || !git.WorktreeIsRegistered(worktreePath) {
+		return false, nil
+	}
+	if branch, err := git.GetWorktreeBranch(worktreePath); err != nil || branch != info.BranchName {
+		return false, nil
+	}
+
+	if !term.IsTerminal(os.Stdin) {
+		return false, nil
+	}
+
+	absPath, _ := filepath.Abs(worktreePath)
+	p := newAddPrompter()
+	idx, err := p.Select(fmt.Sprintf("A worktree for PR #%d already exists at %s:", info.Number, absPath), "", []string{
+		"Use the existing worktree",
+		"Recreate it",
+	})
+	if err != nil {
+		return false, fmt.Errorf("prompt failed: %w", err)
+	}
+	if idx != 0 {
+		return false, nil
+	}
+
+	recordRecent(absPath)
+	if Log.Quiet() {
+		Log.Plainf("%s\n", absPath)
+	}
+	Log.Outf(logger.Green, "\nUsing existing worktree.\n")
+	Log.Outf(logger.Default, "Location: %s\n", absPath)
+	Log.Outf(logger.Default, "\nTo switch to the worktree:\n")
+	Log.Outf(logger.Cyan, "  %s\n", cdCommand(absPath))
+
+	if openFlag {
+		openInEditor(editorCmd, absPath)
+	}
+	if webFlag {
+		openInBrowser(info)
+	}
+	runActionOrArgs(info, absPath)
+	printPath(absPath)
+	return true, nil
+}
+
+// worktreeLayout returns the base directory and path template worktrees of the current repository use.
+// In the bare layout created by clone, worktrees live next to the .bare directory.
+func worktreeLayout(cfg config.Config) (baseDir, pathTemplate string) {
+	if root, ok := git.BareLayoutRoot(); ok {
+		return root, "{name}"
+	}
+	return cfg.WorktreeBase, cfg.WorktreePathTemplate
+}
+
+// updateSubmodules checks out the submodules of the new worktree, which git worktree add leaves empty.
+// Failures are reported as warnings since the worktree itself was created successfully.
+func updateSubmodules(ctx context.Context, worktreePath string) {
+	if _, err := os.Stat(filepath.Join(worktreePath, ".gitmodules")); err != nil {
+		return
+	}
+	Log.Infof("Updating submodules...\n")
+	if err := git.SubmoduleUpdate(ctx, worktreePath); err != nil {
+		Log.Warnf("⚠️  Failed to update submodules: %v\n", err)
+	}
+}
+
+// copyUntrackedFiles copies files matching the configured patterns from the main worktree.
+// Failures are reported as warnings since the worktree itself was created successfully.
+func copyUntrackedFiles(patterns []string, respectGitignore bool, worktreePath string) {
+	mainPath, err := git.GetMainWorktreeDir()
+	if err != nil {
+		Log.Warnf("⚠️  Could not find main worktree to copy files from: %v\n", err)
+		return
+	}
+
+	Log.Infof("Copying files from %s...\n", mainPath)
+	if err := worktree.CopyFiles(mainPath, worktreePath, patterns, respectGitignore); err != nil {
+		Log.Warnf("⚠️  Failed to copy files: %v\n", err)
+	}
+}
+
+// linkSharedDirs links the configured directories of the main worktree into the new worktree.
+// Failures are reported as warnings since the worktree itself was created successfully.
+func linkSharedDirs(dirs []string, worktreePath string) {
+	mainPath, err := git.GetMainWorktreeDir()
+	if err != nil {
+		Log.Warnf("⚠️  Could not find main worktree to link directories from: %v\n", err)
+		return
+	}
+
+	Log.Infof("Linking directories from %s...\n", mainPath)
+	existing, err := worktree.LinkDirs(mainPath, worktreePath, dirs)
+	for _, dir := range existing {
+		Log.Warnf("⚠️  Not linking '%s': it already exists in the worktree\n", dir)
+	}
+	if err != nil {
+		Log.Warnf("⚠️  Failed to link directories: %v\n", err)
+	}
+}
+
+// allowDirenv trusts the .envrc of the new worktree, which may have been copied from the main worktree.
+func allowDirenv(worktreePath string) {
+	if !direnv.HasEnvrc(worktreePath) {
+		return
+	}
+	if !direnv.Installed() {
+		Log.Warnf("⚠️  Skipping direnv allow: direnv is not installed\n")
+		return
+	}
+	Log.Infof("Running direnv allow...\n")
+	if err := direnv.Allow(worktreePath); err != nil {
+		Log.Warnf("⚠️  %v\n", err)
+	}
+}
+
+// runPostCreateHook runs the configured hook inside the new worktree.
+// A failing hook only produces a warning and leaves the worktree in place.
+func runPostCreateHook(hook, worktreePath string, info *worktree.WorktreeInfo) {
+	Log.Outf(logger.Magenta, "\nRunning post-create hook: %s\n", hook)
+
+	env := append(os.Environ(),
+		"GH_WORKTREE_PATH="+worktreePath,
+		"GH_WORKTREE_BRANCH="+info.BranchName,
+		"GH_WORKTREE_TYPE="+string(info.Type),
+	)
+
+	if err := execext.RunCommand(context.Background(), &execext.RunCommandOptions{
+		Command: hook,
+		Dir:     worktreePath,
+		Env:     env,
+		Stdin:   os.Stdin,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+	}); err != nil {
+		Log.Warnf("\n⚠️  Post-create hook failed: %v\n", err)
+	}
+}
+
+// openInEditor opens the worktree in the resolved editor.
+// If no editor is available a hint is printed instead of failing.
+func openInEditor(configured, worktreePath string) {
+	editorCmd := editor.Resolve(configured)
+	Log.Infof("Opening %s in %s...\n", worktreePath, editorCmd)
+	if err := editor.Open(editorCmd, worktreePath); err != nil {
+		if errors.Is(err, editor.ErrNoEditor) {
+			Log.Warnf("⚠️  Editor '%s' not found. Set 'editor' in your config or $EDITOR, then open:\n  %s\n", editorCmd, worktreePath)
+			return
+		}
+		Log.Warnf("⚠️  Failed to open editor: %v\n", err)
+	}
+}
+
+// openInBrowser opens the worktree's PR or issue in the browser.
+// Local worktrees have nothing to open, so only a warning is printed.
+func openInBrowser(info *worktree.WorktreeInfo) {
+	var kind string
+	switch info.Type {
+	case worktree.PR:
+		kind = "pr"
+	case worktree.Issue:
+		kind = "issue"
+	default:
+		Log.Warnf("⚠️  Ignoring --web: '%s' is not a PR or issue worktree\n", info.WorktreeName)
+		return
+	}
+
+	// The URL names the repository, which need not be the one in the current directory.
+	args := []string{kind, "view", info.URL, "--web"}
+	if info.URL == "" {
+		args = []string{kind, "view", strconv.Itoa(info.Number), "--web"}
+		if info.Owner != "" && info.Repo != "" {
+			args = append(args, "--repo", info.Owner+"/"+info.Repo)
+		}
+	}
+	if _, stderr, err := ghExec(args...); err != nil {
+		Log.Warnf("⚠️  %v\n", ghError("failed to open in browser", err, stderr))
+	}
+}
+
+// openInTmux opens the worktree in a new tmux window, falling back to the normal
+// success message if the window cannot be created.
+func openInTmux(name, worktreePath string) {
+	if err := tmux.NewWindow(name, worktreePath); err != nil {
+		Log.Warnf("⚠️  Failed to open tmux window: %v\n", err)
+		printSuccess(worktreePath)
+		return
+	}
+	if Log.Quiet() {
+		Log.Plainf("%s\n", worktreePath)
+		return
+	}
+	Log.Outf(logger.Green, "\nWorktree created successfully!\n")
+	Log.Outf(logger.Default, "Location: %s\n", worktreePath)
+	Log.Outf(logger.Cyan, "Opened in tmux window '%s'\n", name)
 }
 
 // printSuccess prints the final success message.
 func printSuccess(path string) {
+	if Log.Quiet() {
+		Log.Plainf("%s\n", path)
+		return
+	}
 	Log.Outf(logger.Green, "\nWorktree created successfully!\n")
 	Log.Outf(logger.Default, "Location: %s\n", path)
 	Log.Outf(logger.Default, "\nTo switch to the worktree:\n")
-	Log.Outf(logger.Cyan, "  cd %s\n", path)
+	Log.Outf(logger.Cyan, "  %s\n", cdCommand(path))
+}
+
+// cdCommand returns a cd command for path, quoted for --shell or the shell in $SHELL.
+func cdCommand(path string) string {
+	sh := shell.Detect()
+	if shellFlag != "" {
+		// Validated when the command starts.
+		sh, _ = shell.Parse(shellFlag)
+	}
+	return shell.Cd(sh, path)
 }
 
-// SanitizeBranchName is moved from types.go.
+// SanitizeBranchName replaces characters that are not allowed in branch names with underscores.
+// Slashes are kept for namespaced branches like "feature/login", but leading,
+// trailing, and repeated slashes are removed, as are leading dashes, which git
+// would take for an option.
 func SanitizeBranchName(name string) string {
-	invalidChars := regexp.MustCompile(`[^a-zA-Z0-9_-]`)
-	return invalidChars.ReplaceAllString(name, "_")
+	invalidChars := regexp.MustCompile(`[^a-zA-Z0-9_/-]`)
+	repeatedSlashes := regexp.MustCompile(`/{2,}`)
+	name = invalidChars.ReplaceAllString(name, "_")
+	name = repeatedSlashes.ReplaceAllString(name, "/")
+	return strings.TrimRight(strings.TrimLeft(name, "/-"), "/")
 }
 
-// DetermineWorktreeType determines the type of worktree based on the input
-// Returns the worktree type and an error message if invalid.
-func DetermineWorktreeType(input string) (worktree.WorktreeType, error) {
-	u, err := url.Parse(input)
-	if err != nil {
-		return worktree.Local, nil
+// SanitizeWorktreeName returns a directory name for a worktree.
+// Path separators are replaced so that "feature/login" becomes "feature_login",
+// and the result is made valid on Windows as well (see worktree.SafeName).
+func SanitizeWorktreeName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == '\\' })
+	return worktree.SafeName(strings.Join(parts, "_"))
+}
+
+// validateWorktreeName checks that name, as given with --name, can be used as is for a
+// worktree directory.
+func validateWorktreeName(name string) error {
+	switch {
+	case strings.ContainsAny(name, `/\`):
+		return fmt.Errorf("invalid worktree name '%s': must not contain path separators; use --path for a custom location", name)
+	case name == "." || name == ".." || worktree.SafeName(name) != name:
+		return fmt.Errorf("invalid worktree name '%s': not a valid directory name", name)
+	}
+	return nil
+}
+
+// expandShorthand turns an owner/repo#123 or #123 reference into a PR or issue URL, resolving
+// #123 against --repo or the current repository. kind is the expected type; when it is empty, GitHub is
+// asked whether the number is a PR or an issue. Other input is returned unchanged.
+func expandShorthand(input string, kind worktree.WorktreeType) (string, error) {
+	owner, repo, number, ok := ghwt.ParseShorthand(input)
+	if !ok {
+		return input, nil
 	}
 
-	if u.Scheme == "" {
-		return worktree.Local, nil
+	var host string
+	if current, err := ghRepo(); err == nil {
+		host = current.Host
+		if owner == "" {
+			owner, repo = current.Owner, current.Name
+		}
+	} else if owner == "" {
+		return "", fmt.Errorf("cannot resolve '%s' outside of a GitHub repository; use owner/repo#%d", input, number)
+	}
+	if host == "" {
+		host, _ = auth.DefaultHost()
+	}
+
+	ref := ghwt.Ref{Host: host, Owner: owner, Repo: repo, Type: kind, Number: number}
+	if kind == "" {
+		// PRs are issues too in GitHub's API, so one lookup tells them apart.
+		stdout, stderr, err := ghExec("api", "--hostname", host,
+			fmt.Sprintf("repos/%s/%s/issues/%d", owner, repo, number),
+			"--jq", ".pull_request != null")
+		if err != nil {
+			return "", ghError(fmt.Sprintf("failed to look up %s/%s#%d", owner, repo, number), err, stderr)
+		}
+		ref.Type = worktree.Issue
+		if strings.TrimSpace(stdout.String()) == "true" {
+			ref.Type = worktree.PR
+		}
 	}
+	return ref.URL(), nil
+}
 
-	if u.Scheme != "http" && u.Scheme != "https" {
-		return worktree.Local, nil
+// ghRepo returns the repository PR and issue numbers refer to: --repo when given, otherwise the
+// repository of the current directory.
+func ghRepo() (repository.Repository, error) {
+	if repoFlag != "" {
+		return repository.Parse(repoFlag)
 	}
+	return repository.Current()
+}
 
-	prPattern := regexp.MustCompile(`^/[^/]+/[^/]+/pull/\d+(?:/.*)?$`)
-	if prPattern.MatchString(u.Path) {
-		return worktree.PR, nil
+// qualifyNumber turns a bare PR or issue number into a URL for --repo, so that it does not depend
+// on the current directory. Other input is returned unchanged.
+func qualifyNumber(value string, kind worktree.WorktreeType) (string, error) {
+	if _, err := strconv.Atoi(value); err != nil || repoFlag == "" {
+		return value, nil
 	}
+	return expandShorthand("#"+value, kind)
+}
 
-	issuePattern := regexp.MustCompile(`^/[^/]+/[^/]+/issues/\d+(?:/.*)?$`)
-	if issuePattern.MatchString(u.Path) {
-		return worktree.Issue, nil
+// repoArgs returns the --repo arguments for gh commands that take value, which a URL makes redundant.
+func repoArgs(value string) []string {
+	if repoFlag == "" {
+		return nil
 	}
+	if _, ok := ghwt.ParseURL(value); ok {
+		return nil
+	}
+	return []string{"--repo", repoFlag}
+}
 
-	return worktree.Local, nil
+// normalizeRef returns the canonical URL for PR and issue URLs and the input unchanged otherwise.
+func normalizeRef(value string) string {
+	if ref, ok := ghwt.ParseURL(value); ok {
+		return ref.URL()
+	}
+	return value
 }
 
 var (
 	useExistingFlag bool
 	prFlag          string
+	prAuthorFlag    string
+	prLabelFlag     string
+	repoFlag        string
 	issueFlag       string
 	actionFlag      string
+	openFlag        bool
+	tmuxFlag        bool
+	draftPRFlag     bool
+	printPathFlag   bool
+	baseFlag        string
+	noFetchFlag     bool
+	depthFlag       int
+	remoteFlag      string
+	webFlag         bool
+	trackFlag       bool
+	noTrackFlag     bool
+	sparseFlag      []string
+	detachFlag      bool
+	pathFlag        string
+	nameFlag        string
+	limitFlag       int
+	shellFlag       string
+	submodulesFlag  bool
 )
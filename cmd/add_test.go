@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/worktree"
+)
+
+func TestSanitizeBranchName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"feature/login", "feature/login"},
+		{"bug/ISSUE-1/fix", "bug/ISSUE-1/fix"},
+		{"my branch", "my_branch"},
+		{"ünïcödé", "_n_c_d_"},
+		{"-leading-dash", "leading-dash"},
+		{"--/x", "x"},
+		{"a..b", "a__b"},
+		{"..", "__"},
+		{".hidden", "_hidden"},
+		{"branch.lock", "branch_lock"},
+		{"feature//login", "feature/login"},
+		{"/bad/", "bad"},
+		{"//a///b//", "a/b"},
+		{"feat@{1}~^:?*[x]\\", "feat__1_______x__"},
+	}
+	for _, tt := range tests {
+		got := SanitizeBranchName(tt.name)
+		if got != tt.want {
+			t.Errorf("SanitizeBranchName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+		if !git.IsValidRefName(got) {
+			t.Errorf("SanitizeBranchName(%q) = %q is not a valid branch name", tt.name, got)
+		}
+	}
+}
+
+func TestAddExistingBranchForce(t *testing.T) {
+	repo, base := setupRepo(t)
+	runGit(t, repo, "branch", "feature")
+	runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "main moves on")
+	head := runGit(t, repo, "rev-parse", "HEAD")
+	old := runGit(t, repo, "rev-parse", "feature")
+	path := filepath.Join(base, "r", "feature")
+
+	// Without a terminal, an existing branch is a conflict unless --force is given.
+	asked := stubPrompts(t, false, false)
+	err := createFromLocal("feature")
+	if code := exitCode(err); code != ExitConflict {
+		t.Fatalf("createFromLocal() without --force = %v (exit code %d), want exit code %d", err, code, ExitConflict)
+	}
+	if got := runGit(t, repo, "rev-parse", "feature"); got != old || exists(path) {
+		t.Fatal("the existing branch was changed without --force")
+	}
+
+	setFlag(t, &forceFlag, true)
+	if err := createFromLocal("feature"); err != nil {
+		t.Fatalf("createFromLocal() with --force error = %v", err)
+	}
+	if len(*asked) != 0 {
+		t.Errorf("--force asked %q", *asked)
+	}
+	if got := runGit(t, repo, "rev-parse", "feature"); got != head {
+		t.Errorf("feature = %s, want it recreated at HEAD %s", got, head)
+	}
+	if got := runGit(t, path, "branch", "--show-current"); got != "feature" {
+		t.Errorf("worktree is on %q, want %q", got, "feature")
+	}
+}
+
+// setupOrigin creates a repository to clone from at o/r in root, standing in for github.com/o/r. It has a
+// main branch and a PR #5 whose head is published only as refs/pull/5/head, as it is once the
+// PR's branch has been deleted, and returns its path and the PR's head commit.
+func setupOrigin(t *testing.T, root string) (origin, head string) {
+	t.Helper()
+	origin = filepath.Join(root, "o", "r")
+	runGit(t, root, "init", "-q", "-b", "main", origin)
+	runGit(t, origin, "commit", "-q", "--allow-empty", "-m", "init")
+	runGit(t, origin, "checkout", "-q", "-b", "five")
+	runGit(t, origin, "commit", "-q", "--allow-empty", "-m", "PR five")
+	head = runGit(t, origin, "rev-parse", "HEAD")
+	runGit(t, origin, "update-ref", "refs/pull/5/head", head)
+	runGit(t, origin, "checkout", "-q", "main")
+	runGit(t, origin, "branch", "-q", "-D", "five")
+	return origin, head
+}
+
+// fakePRView is a fake gh script that describes PR #5 of o/r with the given head commit.
+func fakePRView(head string) string {
+	return `case "$1 $2" in
+"pr view") echo '{"number":5,"title":"PR five","headRefName":"five","headRefOid":"` + head + `","url":"https://github.com/o/r/pull/5","baseRefName":"main","author":{"login":"octo"},"headRepositoryOwner":{"login":"o"},"headRepository":{"name":"r"}}';;
+*) echo "unexpected gh $*" >&2; exit 1;;
+esac`
+}
+
+func TestAddPRURLOutsideRepository(t *testing.T) {
+	root, base := setupConfig(t)
+	origin, head := setupOrigin(t, root)
+	log := fakeGh(t, fakePRView(head))
+
+	// The clone made by gh wt clone: a bare repository in <worktree_dir>/<repo>/.bare.
+	t.Chdir(root)
+	layout := filepath.Join(base, "r")
+	if err := cloneBare(repository.Repository{Host: "github.com", Owner: "o", Name: "r"}, origin, layout); err != nil {
+		t.Fatalf("cloneBare() error = %v", err)
+	}
+
+	outside := filepath.Join(root, "elsewhere")
+	if err := os.Mkdir(outside, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CEILING_DIRECTORIES", root)
+	t.Chdir(outside)
+
+	if err := createFromArg("https://github.com/o/r/pull/5"); err != nil {
+		t.Fatalf("createFromArg() error = %v", err)
+	}
+
+	path := filepath.Join(layout, "pr_5")
+	if got := runGit(t, path, "rev-parse", "HEAD"); got != head {
+		t.Errorf("worktree HEAD = %s, want the PR head %s", got, head)
+	}
+	if got := runGit(t, path, "branch", "--show-current"); got != "five" {
+		t.Errorf("worktree is on %q, want %q", got, "five")
+	}
+	if got := runGit(t, path, "rev-parse", "--path-format=absolute", "--git-common-dir"); got != filepath.Join(layout, ".bare") {
+		t.Errorf("worktree belongs to %s, want the clone at %s", got, layout)
+	}
+	if calls := ghCalls(t, log); len(calls) != 1 || !strings.HasPrefix(calls[0], "pr view https://github.com/o/r/pull/5 ") {
+		t.Errorf("gh calls = %q, want a single pr view of the URL", calls)
+	}
+}
+
+func TestAddPRURLOutsideRepositoryOtherClone(t *testing.T) {
+	root, base := setupConfig(t)
+	_, head := setupOrigin(t, root)
+	log := fakeGh(t, fakePRView(head))
+
+	// A clone of bob/r has the same directory name as a clone of o/r would.
+	other := filepath.Join(root, "bob", "r")
+	runGit(t, root, "init", "-q", "-b", "main", other)
+	runGit(t, other, "commit", "-q", "--allow-empty", "-m", "init")
+	t.Chdir(root)
+	layout := filepath.Join(base, "r")
+	if err := cloneBare(repository.Repository{Host: "github.com", Owner: "bob", Name: "r"}, other, layout); err != nil {
+		t.Fatalf("cloneBare() error = %v", err)
+	}
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(root))
+	t.Chdir(root)
+
+	err := createFromArg("https://github.com/o/r/pull/5")
+	if code := exitCode(err); code != ExitConflict || !strings.Contains(err.Error(), "not a clone of o/r") {
+		t.Errorf("createFromArg() error = %v (exit code %d), want a conflict naming o/r", err, code)
+	}
+	if exists(filepath.Join(layout, "pr_5")) {
+		t.Error("a worktree was created in the clone of bob/r")
+	}
+	if out := runGit(t, layout, "for-each-ref", "refs/gh-wt"); out != "" {
+		t.Errorf("the PR was fetched into the clone of bob/r: %s", out)
+	}
+	if calls := ghCalls(t, log); len(calls) != 1 {
+		t.Errorf("gh calls = %q, want only the PR lookup", calls)
+	}
+}
+
+func TestAddPRURLOutsideRepositoryWithoutClone(t *testing.T) {
+	root, base := setupConfig(t)
+	_, head := setupOrigin(t, root)
+	fakeGh(t, fakePRView(head))
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(root))
+	t.Chdir(root)
+
+	err := createFromArg("https://github.com/o/r/pull/5")
+	if err == nil || !strings.Contains(err.Error(), "gh wt clone o/r") {
+		t.Errorf("createFromArg() error = %v, want a hint to clone o/r", err)
+	}
+	if exists(filepath.Join(base, "r")) {
+		t.Error("a clone was made without asking")
+	}
+}
+
+func TestAddFromLinkedWorktree(t *testing.T) {
+	repo, base := setupRepo(t)
+	linked := filepath.Join(filepath.Dir(repo), "linked")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "first", linked)
+	t.Chdir(linked)
+
+	if err := createFromLocal("second"); err != nil {
+		t.Fatalf("createFromLocal() error = %v", err)
+	}
+
+	// The new worktree belongs to r, not to a repository named after the linked worktree.
+	path := filepath.Join(base, "r", "second")
+	if got := runGit(t, path, "rev-parse", "--path-format=absolute", "--git-common-dir"); got != filepath.Join(repo, ".git") {
+		t.Errorf("worktree belongs to %s, want %s", got, filepath.Join(repo, ".git"))
+	}
+	if exists(filepath.Join(base, "linked")) || exists(filepath.Join(linked, "second")) {
+		t.Error("the worktree was placed under the linked worktree")
+	}
+}
+
+func TestAddCommitURL(t *testing.T) {
+	root, base := setupConfig(t)
+	origin, head := setupOrigin(t, root)
+	repo := filepath.Join(root, "r")
+	runGit(t, root, "clone", "-q", "--no-local", origin, repo)
+	t.Chdir(repo)
+	// The commit is only reachable from refs/pull/5/head, which a clone does not fetch.
+	if _, err := git.CommandOutput("cat-file", "-e", head); err == nil {
+		t.Fatalf("commit %s is already in the clone", head)
+	}
+
+	if err := createFromArg("https://github.com/o/r/commit/" + head); err != nil {
+		t.Fatalf("createFromArg() error = %v", err)
+	}
+
+	short := runGit(t, repo, "rev-parse", "--short", head)
+	path := filepath.Join(base, "r", "sha_"+short)
+	if got := runGit(t, path, "rev-parse", "HEAD"); got != head {
+		t.Errorf("worktree HEAD = %s, want %s", got, head)
+	}
+	if got := runGit(t, path, "branch", "--show-current"); got != "sha_"+short {
+		t.Errorf("worktree is on %q, want %q", got, "sha_"+short)
+	}
+}
+
+func TestAddUnsupportedURL(t *testing.T) {
+	repo, base := setupRepo(t)
+	for _, url := range []string{
+		"https://github.com/o/r/discussions/5",
+		"https://github.com/o/r",
+	} {
+		err := createFromArg(url)
+		if err == nil || !strings.Contains(err.Error(), "unsupported URL") {
+			t.Errorf("createFromArg(%q) error = %v, want an unsupported URL error", url, err)
+		}
+	}
+	if entries, _ := os.ReadDir(base); len(entries) != 0 {
+		t.Errorf("worktree directory has %d entries, want none", len(entries))
+	}
+	if branches := runGit(t, repo, "branch", "--format=%(refname:short)"); branches != "main" {
+		t.Errorf("branches = %q, want only main", branches)
+	}
+}
+
+func TestAddBranchCheckedOutElsewhere(t *testing.T) {
+	repo, base := setupRepo(t)
+	linked := filepath.Join(filepath.Dir(repo), "linked")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "feature", linked)
+	mainHead := runGit(t, repo, "rev-parse", "main")
+
+	tests := []struct {
+		name   string
+		branch string
+		other  string
+		force  bool
+	}{
+		{"main worktree", "main", repo, false},
+		{"linked worktree", "feature", linked, false},
+		{"with --force", "main", repo, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setFlag(t, &forceFlag, tt.force)
+			stubPrompts(t, false, true)
+
+			err := createFromLocal(tt.branch)
+			if code := exitCode(err); code != ExitConflict {
+				t.Errorf("exit code = %d, want %d (error %v)", code, ExitConflict, err)
+			}
+			if err == nil || !strings.Contains(err.Error(), "already checked out in the worktree at "+tt.other) {
+				t.Errorf("createFromLocal(%q) error = %v, want it to name %s", tt.branch, err, tt.other)
+			}
+			if exists(filepath.Join(base, "r", tt.branch)) {
+				t.Error("a worktree directory was created")
+			}
+		})
+	}
+	if got := runGit(t, repo, "rev-parse", "main"); got != mainHead {
+		t.Errorf("main moved from %s to %s", mainHead, got)
+	}
+}
+
+func TestOpenInBrowser(t *testing.T) {
+	setupConfig(t)
+	log := fakeGh(t, "exit 0")
+
+	openInBrowser(&worktree.WorktreeInfo{Type: worktree.PR, Owner: "o", Repo: "r", Number: 5, URL: "https://github.com/o/r/pull/5"})
+	openInBrowser(&worktree.WorktreeInfo{Type: worktree.Issue, Owner: "o", Repo: "r", Number: 7})
+
+	want := []string{
+		"pr view https://github.com/o/r/pull/5 --web",
+		"issue view 7 --web --repo o/r",
+	}
+	if got := ghCalls(t, log); !slices.Equal(got, want) {
+		t.Errorf("gh calls = %q, want %q", got, want)
+	}
+}
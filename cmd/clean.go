@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/logger"
+	"github.com/ffalor/gh-wt/pkg/ghwt"
+	"github.com/spf13/cobra"
+)
+
+// cleanCmd represents the clean command.
+var cleanCmd = &cobra.Command{
+	Use:   "clean --older-than <age>",
+	Short: "Remove worktrees that have not been touched for a while",
+	Long: `Remove worktrees of the current repository whose last commit and directory are both
+older than the given age, such as 30d, 2w, or 24h. The main worktree is never removed.
+
+Worktrees with uncommitted changes and locked worktrees are skipped. Each removal is
+confirmed unless --force is used. Branches with unmerged commits are kept.`,
+	Args: cobra.NoArgs,
+	RunE: runClean,
+}
+
+var olderThanFlag string
+
+func init() {
+	cleanCmd.Flags().StringVar(&olderThanFlag, "older-than", "", "remove worktrees not touched for this long, e.g. 30d, 2w, or 24h")
+	_ = cleanCmd.MarkFlagRequired("older-than")
+	rootCmd.AddCommand(cleanCmd)
+}
+
+func runClean(cmd *cobra.Command, args []string) error {
+	if !git.IsGitRepository(".") {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	age, err := parseAge(olderThanFlag)
+	if err != nil {
+		return err
+	}
+	if !forceFlag && !stdinIsTerminal() {
+		return fmt.Errorf("cannot confirm removals without a terminal; use --force")
+	}
+
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-age)
+
+	// The first entry is always the main worktree.
+	removed, kept, skipped, failed := 0, 0, 0, 0
+	for _, wt := range worktrees[1:] {
+		lastUsed, ok := lastActivity(wt.Path)
+		if !ok {
+			Log.Warnf("Skipping '%s': cannot tell when it was last used.\n", wt.Path)
+			skipped++
+			continue
+		}
+		if lastUsed.After(cutoff) {
+			kept++
+			continue
+		}
+
+		if wt.Locked {
+			Log.Warnf("Skipping '%s': worktree is locked.\n", wt.Path)
+			skipped++
+			continue
+		}
+		if git.HasUncommittedChanges(wt.Path) {
+			Log.Warnf("Skipping '%s': worktree has uncommitted changes.\n", wt.Path)
+			skipped++
+			continue
+		}
+
+		if !forceFlag {
+			confirmed, err := confirm(fmt.Sprintf("Worktree '%s' was last used %s ago. Remove it?", wt.Path, formatAge(time.Since(lastUsed))), true)
+			if err != nil {
+				return fmt.Errorf("prompt failed: %w", err)
+			}
+			if !confirmed {
+				kept++
+				continue
+			}
+		}
+
+		// Unmerged branches are kept so that abandoned but unpushed work can still be recovered,
+		// which also makes asking about unpushed commits after "Remove it?" unnecessary.
+		ok, err := removeWorktree(wt, removeOptions{SkipUnpushedCheck: true})
+		switch {
+		case ok && errors.Is(err, ghwt.ErrBranchNotDeleted):
+			Log.Warnf("⚠️  %v; the branch is kept so that its commits can be recovered\n", err)
+			removed++
+		case ok && err != nil:
+			Log.Warnf("⚠️  %v\n", err)
+			removed++
+		case err != nil:
+			Log.Errorf("Failed to remove '%s': %v\n", wt.Path, err)
+			failed++
+		default:
+			removed++
+		}
+	}
+
+	Log.Outf(logger.Green, "\nRemoved %d worktree(s), kept %d, skipped %d.\n", removed, kept, skipped)
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d worktree(s)", failed)
+	}
+	return nil
+}
+
+// lastActivity returns when the worktree at path was last used: the later of its last commit and
+// the modification time of its directory.
+func lastActivity(path string) (time.Time, bool) {
+	var last time.Time
+	if t, err := git.LastCommitTime(path); err == nil {
+		last = t
+	}
+	if fi, err := os.Stat(path); err == nil && fi.ModTime().After(last) {
+		last = fi.ModTime()
+	}
+	return last, !last.IsZero()
+}
+
+// parseAge parses an age such as 30d, 2w, or 24h. Days and weeks are added to the units
+// time.ParseDuration accepts.
+func parseAge(s string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count <= 0 {
+				return 0, fmt.Errorf("invalid age '%s': expected a positive number of %s, e.g. 30d, 2w, or 24h", s, suffix)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age '%s': expected e.g. 30d, 2w, or 24h", s)
+	}
+	return d, nil
+}
+
+// formatAge formats d in the largest whole unit of days, hours, or minutes.
+func formatAge(d time.Duration) string {
+	if days := int(d.Hours() / 24); days > 0 {
+		return fmt.Sprintf("%d day(s)", days)
+	}
+	if hours := int(d.Hours()); hours > 0 {
+		return fmt.Sprintf("%d hour(s)", hours)
+	}
+	return fmt.Sprintf("%d minute(s)", int(d.Minutes()))
+}
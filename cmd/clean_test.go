@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ffalor/gh-wt/internal/git"
+)
+
+func TestCleanKeepsUnmergedBranch(t *testing.T) {
+	repo, base := setupRepo(t)
+	path := filepath.Join(base, "r", "abandoned")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "abandoned", path)
+	old := time.Now().AddDate(0, 0, -60)
+	t.Setenv("GIT_COMMITTER_DATE", old.Format(time.RFC3339))
+	runGit(t, path, "commit", "-q", "--allow-empty", "-m", "only here")
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, &olderThanFlag, "30d")
+
+	// Only the removal is confirmed: the unmerged branch is kept, so its commits are not lost.
+	asked := stubPrompts(t, true, true)
+	if err := runClean(cleanCmd, nil); err != nil {
+		t.Fatalf("runClean() error = %v", err)
+	}
+	if len(*asked) != 1 {
+		t.Errorf("asked %d questions, want 1: %q", len(*asked), *asked)
+	}
+	if exists(path) {
+		t.Error("the old worktree was not removed")
+	}
+	if !git.BranchExists("abandoned") {
+		t.Error("the unmerged branch was deleted")
+	}
+}
@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/ffalor/gh-wt/internal/config"
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/logger"
+	"github.com/ffalor/gh-wt/internal/shell"
+	"github.com/ffalor/gh-wt/pkg/ghwt"
+	"github.com/spf13/cobra"
+)
+
+// cloneCmd represents the clone command.
+var cloneCmd = &cobra.Command{
+	Use:   "clone <repo>",
+	Short: "Clone a repository as a bare repo with worktrees next to it",
+	Long: `Clone a repository into the "one bare repo + many worktrees" layout:
+
+  <worktree_dir>/<repo>/.bare     the bare repository
+  <worktree_dir>/<repo>/.git      points git at .bare
+  <worktree_dir>/<repo>/<branch>  a worktree for the default branch
+
+Worktrees created from inside this layout are added next to .bare. The default branch worktree
+is skipped with --checkout-default=false or clone_default_worktree: false in the config.
+
+Examples:
+  gh wt clone owner/repo
+  gh wt clone https://github.com/owner/repo`,
+	Args: cobra.ExactArgs(1),
+	RunE: runClone,
+}
+
+var (
+	protocolFlag        string
+	checkoutDefaultFlag bool
+)
+
+func init() {
+	cloneCmd.Flags().StringVar(&protocolFlag, "protocol", "", "protocol to clone with: https or ssh (default from clone_protocol or gh's git_protocol)")
+	cloneCmd.Flags().BoolVar(&checkoutDefaultFlag, "checkout-default", false, "add a worktree for the default branch; =false leaves only the bare repository (default from clone_default_worktree)")
+	cloneCmd.Flags().StringVar(&shellFlag, "shell", "", "shell to quote the printed cd command for: bash, zsh, fish, or powershell (default from $SHELL)")
+	rootCmd.AddCommand(cloneCmd)
+}
+
+func runClone(cmd *cobra.Command, args []string) error {
+	if shellFlag != "" {
+		if _, err := shell.Parse(shellFlag); err != nil {
+			return withExitCode(ExitUsage, fmt.Errorf("invalid --shell: %w", err))
+		}
+	}
+	cfg, err := config.Get()
+	if err != nil {
+		return err
+	}
+
+	repo, err := repository.Parse(args[0])
+	if err != nil {
+		return withExitCode(ExitUsage, fmt.Errorf("invalid repository '%s': %w", args[0], err))
+	}
+	url, err := cloneURL(repo, cfg.CloneProtocol)
+	if err != nil {
+		return err
+	}
+
+	root := filepath.Join(cfg.WorktreeBase, repo.Name)
+	if _, err := os.Stat(root); err == nil {
+		return withExitCode(ExitConflict, fmt.Errorf("directory already exists: %s", root))
+	}
+	if err := cloneBare(repo, url, root); err != nil {
+		return err
+	}
+
+	checkoutDefault := cfg.CloneDefaultWorktree
+	if cmd.Flags().Changed("checkout-default") {
+		checkoutDefault = checkoutDefaultFlag
+	}
+	if !checkoutDefault {
+		Log.Outf(logger.Green, "\nRepository cloned successfully!\n")
+		Log.Outf(logger.Default, "Location: %s\n", root)
+		Log.Outf(logger.Default, "\nTo add worktrees from it:\n")
+		Log.Outf(logger.Cyan, "  %s\n", cdCommand(root))
+		return nil
+	}
+
+	worktreePath, err := addDefaultWorktree(root)
+	if err != nil {
+		return err
+	}
+
+	Log.Outf(logger.Green, "\nRepository cloned successfully!\n")
+	Log.Outf(logger.Default, "Location: %s\n", root)
+	Log.Outf(logger.Default, "\nTo switch to the default branch worktree:\n")
+	Log.Outf(logger.Cyan, "  %s\n", cdCommand(worktreePath))
+
+	return nil
+}
+
+// addDefaultWorktree adds a worktree for the default branch of the bare layout clone at root,
+// next to .bare, and returns its path.
+func addDefaultWorktree(root string) (string, error) {
+	ctx, cancel := gitContext()
+	defer cancel()
+	defaultBranch, err := git.GetCurrentBranch(filepath.Join(root, git.BareDirName))
+	if err != nil {
+		return "", fmt.Errorf("failed to determine default branch: %w", err)
+	}
+
+	worktreePath := filepath.Join(root, SanitizeWorktreeName(defaultBranch))
+	Log.Infof("Adding a worktree for the default branch '%s'...\n", defaultBranch)
+	if err := git.WorktreeAddFromBranch(ctx, defaultBranch, worktreePath); err != nil {
+		return "", fmt.Errorf("failed to create worktree: %w", err)
+	}
+	if err := git.SetUpstream(defaultBranch, "origin", defaultBranch); err != nil {
+		Log.Warnf("⚠️  Failed to set upstream for '%s': %v\n", defaultBranch, err)
+	}
+	return worktreePath, nil
+}
+
+// cloneBare clones repo from url into the bare layout at root, fetches its branches, and changes
+// into root so that the following git commands run against the new layout.
+func cloneBare(repo repository.Repository, url, root string) (err error) {
+	bareDir := filepath.Join(root, git.BareDirName)
+
+	// A partial clone would block the next attempt, so root is removed again if this created it.
+	if _, statErr := os.Stat(root); os.IsNotExist(statErr) {
+		wd, _ := os.Getwd()
+		defer func() {
+			if err == nil {
+				return
+			}
+			if wd != "" {
+				_ = os.Chdir(wd)
+			}
+			if rmErr := os.RemoveAll(root); rmErr != nil {
+				Log.Warnf("⚠️  Failed to remove %s: %v\n", root, rmErr)
+			}
+		}()
+	}
+
+	// Cloning can take much longer than git_timeout allows, so it is not limited.
+	Log.Infof("Cloning %s/%s into %s...\n", repo.Owner, repo.Name, bareDir)
+	if err := git.CloneBare(context.Background(), url, bareDir); err != nil {
+		return fmt.Errorf("failed to clone: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, ".git"), []byte("gitdir: ./"+git.BareDirName+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write .git file: %w", err)
+	}
+	if err := git.ConfigRemote(bareDir, "origin"); err != nil {
+		return fmt.Errorf("failed to configure remote: %w", err)
+	}
+
+	if err := os.Chdir(root); err != nil {
+		return err
+	}
+
+	ctx, cancel := gitContext()
+	defer cancel()
+	Log.Infof("Fetching remote branches...\n")
+	if err := git.Fetch(ctx, "origin"); err != nil {
+		return fmt.Errorf("failed to fetch: %w", err)
+	}
+
+	// A bare clone's HEAD is the remote's default branch, which issue worktrees start from.
+	if defaultBranch, err := git.GetCurrentBranch(bareDir); err == nil {
+		_ = git.SetRemoteHead("origin", defaultBranch)
+	}
+	return nil
+}
+
+// ensureLocalRepo makes sure git commands run in a clone of repo. Outside a git repository it
+// changes into the bare layout clone in the worktree directory, offering to create it first if it
+// does not exist yet.
+func ensureLocalRepo(repo ghwt.Ref) error {
+	if git.IsGitRepository(".") {
+		return nil
+	}
+	cfg, err := config.Get()
+	if err != nil {
+		return err
+	}
+
+	root := filepath.Join(cfg.WorktreeBase, repo.Repo)
+	if _, err := os.Stat(filepath.Join(root, git.BareDirName)); err == nil {
+		// Clones are found by repository name only, so another owner's repository may have it too.
+		if url, ok := isCloneOf(root, repo); !ok {
+			return withExitCode(ExitConflict, fmt.Errorf("%s is not a clone of %s/%s (its origin is '%s'); run this command inside a clone of %s/%s", root, repo.Owner, repo.Repo, url, repo.Owner, repo.Repo))
+		}
+		Log.Infof("Using the clone at %s\n", root)
+		return os.Chdir(root)
+	}
+	if _, err := os.Stat(root); err == nil {
+		return fmt.Errorf("not in a git repository, and %s exists but is not a clone made by gh wt clone; run this command inside a clone of %s/%s", root, repo.Owner, repo.Repo)
+	}
+
+	if !forceFlag {
+		if !term.IsTerminal(os.Stdin) {
+			return fmt.Errorf("not in a git repository; run 'gh wt clone %s/%s' first or use --force to clone it", repo.Owner, repo.Repo)
+		}
+		p := newAddPrompter()
+		clone, err := p.Confirm(fmt.Sprintf("Not in a git repository. Clone %s/%s into %s?", repo.Owner, repo.Repo, root), true)
+		if err != nil {
+			return fmt.Errorf("prompt failed: %w", err)
+		}
+		if !clone {
+			return fmt.Errorf("not in a git repository; clone %s/%s first", repo.Owner, repo.Repo)
+		}
+	}
+
+	ghRepo := repository.Repository{Host: repo.Host, Owner: repo.Owner, Name: repo.Repo}
+	url, err := cloneURL(ghRepo, cfg.CloneProtocol)
+	if err != nil {
+		return err
+	}
+	if err := cloneBare(ghRepo, url, root); err != nil {
+		return err
+	}
+	// The worktree being added is what was asked for, so a missing default branch worktree is only a warning.
+	if cfg.CloneDefaultWorktree {
+		if _, err := addDefaultWorktree(root); err != nil {
+			Log.Warnf("⚠️  %v\n", err)
+		}
+	}
+	return nil
+}
+
+// isCloneOf reports whether the bare layout clone at root is a clone of repo, going by the owner
+// and name at the end of its origin URL, and returns that URL.
+func isCloneOf(root string, repo ghwt.Ref) (string, bool) {
+	url, err := git.RemoteURL(filepath.Join(root, git.BareDirName), "origin")
+	if err != nil {
+		return "", false
+	}
+	// scp-like URLs such as git@github.com:owner/repo.git separate the host with a colon.
+	parts := strings.FieldsFunc(strings.TrimSuffix(strings.TrimRight(url, "/"), ".git"), func(r rune) bool { return r == '/' || r == ':' })
+	if len(parts) < 2 {
+		return url, false
+	}
+	return url, strings.EqualFold(parts[len(parts)-2], repo.Owner) && strings.EqualFold(parts[len(parts)-1], repo.Repo)
+}
+
+// cloneURL returns the URL to clone repo with. The protocol comes from --protocol,
+// then the clone_protocol config key, then gh's git_protocol setting for the host.
+func cloneURL(repo repository.Repository, configured string) (string, error) {
+	protocol := protocolFlag
+	if protocol == "" {
+		protocol = configured
+	}
+	if protocol == "" {
+		stdout, _, err := ghExec("config", "get", "git_protocol", "--host", repo.Host)
+		if err == nil {
+			protocol = strings.TrimSpace(stdout.String())
+		}
+	}
+
+	switch protocol {
+	case config.ProtocolSSH:
+		return fmt.Sprintf("git@%s:%s/%s.git", repo.Host, repo.Owner, repo.Name), nil
+	case config.ProtocolHTTPS, "":
+		return fmt.Sprintf("https://%s/%s/%s.git", repo.Host, repo.Owner, repo.Name), nil
+	default:
+		return "", fmt.Errorf("invalid protocol %q: must be %s or %s", protocol, config.ProtocolHTTPS, config.ProtocolSSH)
+	}
+}
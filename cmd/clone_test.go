@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+func TestCloneBareFailureRemovesRoot(t *testing.T) {
+	root, base := setupConfig(t)
+	t.Chdir(root)
+	repo := repository.Repository{Host: "github.com", Owner: "o", Name: "r"}
+	layout := filepath.Join(base, "r")
+
+	if err := cloneBare(repo, filepath.Join(root, "missing"), layout); err == nil {
+		t.Fatal("cloneBare() of a missing repository succeeded")
+	}
+	if exists(layout) {
+		t.Errorf("the failed clone left %s behind", layout)
+	}
+	if wd, _ := os.Getwd(); wd != root {
+		t.Errorf("working directory = %s, want %s", wd, root)
+	}
+
+	// The next attempt is not blocked by the failed one.
+	origin, _ := setupOrigin(t, root)
+	if err := cloneBare(repo, origin, layout); err != nil {
+		t.Fatalf("cloneBare() after a failure error = %v", err)
+	}
+	if !exists(filepath.Join(layout, ".bare")) {
+		t.Error("the clone was not made")
+	}
+}
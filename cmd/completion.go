@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// completeWorktreeNames completes the first argument with the names of the
+// current repository's worktrees, excluding the main worktree.
+func completeWorktreeNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil || len(worktrees) <= 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := make([]string, 0, len(worktrees)-1)
+	for _, wt := range worktrees[1:] {
+		name := filepath.Base(wt.Path)
+		if wt.Branch != "" {
+			name += "\t" + wt.Branch
+		}
+		names = append(names, name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/prompter"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/ffalor/gh-wt/internal/config"
+	"github.com/ffalor/gh-wt/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the configuration file",
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a config file with commented defaults",
+	Long:  `Create a config file with commented defaults. Refuses to overwrite an existing file unless --force is used.`,
+	Args:  cobra.NoArgs,
+	RunE:  runConfigInit,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:       "get <key>",
+	Short:     "Print the value of a config key",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: config.Keys(),
+	RunE:      runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a config key in the config file",
+	Long: `Set a config key in the config file.
+
+List values such as copy_files are given as a comma-separated string.
+The file is rewritten, so comments in it are not preserved.`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return config.Keys(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: runConfigSet,
+}
+
+func init() {
+	configCmd.AddCommand(configInitCmd, configGetCmd, configSetCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// configTemplate is written by config init. %s is replaced with the worktree directory.
+const configTemplate = `# gh wt configuration
+
+# Directory where worktrees are created.
+worktree_dir: %q
+
+# Editor used by --open. Falls back to $EDITOR, then code.
+# editor: "code"
+
+# Name of PR and issue worktrees, and path of worktrees relative to worktree_dir.
+# Placeholders: {owner}, {repo}, {number}, {type}, {branch}, {name}, and for PRs and
+# issues {author}, plus {base}, {head_owner}, and {draft} for PRs
+# worktree_name_template: "{type}_{number}"
+# worktree_path_template: "{repo}/{name}"
+
+# What to do when the worktree directory already exists: prompt, suffix, or error.
+# on_name_collision: prompt
+
+# Untracked files copied from the main worktree into new worktrees.
+# copy_files:
+#   - .env
+#   - .envrc
+
+# Skip ignored paths inside copied directories, unless the directory itself is ignored.
+# copy_respect_gitignore: true
+
+# Directories symlinked from the main worktree into new worktrees.
+# link_dirs:
+#   - .vscode
+
+# Directories new worktrees are limited to with sparse-checkout. Overridden by --sparse.
+# default_sparse_paths:
+#   - services/api
+
+# Shell command run in every new worktree.
+# post_create_hook: "npm install"
+
+# Open new worktrees in a new tmux window when running inside tmux.
+# open_in_tmux: false
+
+# Protocol used by clone: https or ssh. Defaults to gh's git_protocol.
+# clone_protocol: ssh
+
+# Add a worktree for the default branch when cloning into the bare layout.
+# clone_default_worktree: true
+
+# Remote that PRs are fetched from and new branches track.
+# default_remote: origin
+
+# Run 'git fetch --prune <default_remote>' when creating a worktree to drop deleted remote branches.
+# fetch_prune: false
+
+# Initialize submodules in new worktrees, like --recurse-submodules.
+# recurse_submodules: false
+
+# Run 'direnv allow' in new worktrees that contain an .envrc.
+# direnv_allow: false
+
+# What to do when the branch of a new worktree already exists: prompt, attach to check it out
+# as is (like --use-existing), or overwrite to delete and recreate it without asking.
+# default_existing_action: prompt
+
+# How PR worktrees are checked out: git fetches refs/pull/<n>/head, gh runs 'gh pr checkout'
+# in a new detached worktree, which handles fork remotes and branch tracking the way gh does.
+# pr_backend: git
+
+# Start issue branches from HEAD instead of the default branch of default_remote.
+# issue_from_head: false
+
+# Prefixes for the names of new PR, issue, and local branches, e.g. review/ and fix/.
+# branch_prefix_pr: ""
+# branch_prefix_issue: ""
+# branch_prefix_local: ""
+
+# Git executable to run instead of the git on PATH. Also set by $GH_WORKTREE_GIT.
+# git_binary: /usr/local/bin/git
+
+# Timeout for git operations such as fetch. 0 disables it.
+# git_timeout: 60s
+
+# Named command lists run with --action <name>.
+# actions:
+#   - name: tmux
+#     cmds:
+#       - tmux new-session -d -s "{{.BranchName}}" -c "{{.WorktreePath}}"
+`
+
+func runConfigInit(cmd *cobra.Command, args []string) error {
+	configFile, err := config.File()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(configFile); err == nil && !forceFlag {
+		return fmt.Errorf("config file already exists at %s; use --force to overwrite", configFile)
+	}
+
+	worktreeDir := config.DefaultWorktreeBase
+	if term.IsTerminal(os.Stdin) {
+		p := prompter.New(os.Stdin, os.Stdout, os.Stderr)
+		worktreeDir, err = p.Input("Directory to create worktrees in:", config.DefaultWorktreeBase)
+		if err != nil {
+			return fmt.Errorf("prompt failed: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configFile), 0o755); err != nil {
+		return fmt.Errorf("cannot create config directory: %w", err)
+	}
+	if err := os.WriteFile(configFile, fmt.Appendf(nil, configTemplate, worktreeDir), 0o644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	Log.Outf(logger.Green, "Config written to %s\n", configFile)
+	return nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	if !slices.Contains(config.Keys(), key) {
+		return fmt.Errorf("unknown config key '%s'; valid keys: %s", key, strings.Join(config.Keys(), ", "))
+	}
+
+	switch value := config.GetValue(key).(type) {
+	case nil:
+	case []any:
+		for _, item := range value {
+			Log.Plainf("%v\n", item)
+		}
+	case []string:
+		for _, item := range value {
+			Log.Plainf("%s\n", item)
+		}
+	default:
+		Log.Plainf("%v\n", value)
+	}
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	value, err := config.ParseValue(key, args[1])
+	if err != nil {
+		return err
+	}
+
+	if err := config.SaveValue(key, value); err != nil {
+		return err
+	}
+
+	// Validate the result so typos such as an invalid duration are reported right away.
+	if _, err := config.Get(); err != nil {
+		Log.Warnf("⚠️  The config is now invalid: %v\n", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ffalor/gh-wt/internal/config"
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/logger"
+	"github.com/ffalor/gh-wt/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd represents the doctor command.
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose common setup problems",
+	Long: `Check that git and gh are installed and recent enough, that gh is authenticated,
+that the config is valid and the worktree directory is writable, and, inside a
+repository, that there are no stale worktree records or orphaned directories.
+
+Exits non-zero if a critical check fails. Warnings do not affect the exit status.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// minGhVersion is the oldest gh every command works with. See git.MinVersion for git.
+var minGhVersion = git.VersionNumber{Major: 2, Minor: 22}
+
+// checkResult is the outcome of a doctor check.
+type checkResult int
+
+const (
+	checkOK checkResult = iota
+	checkSkipped
+	checkWarn
+	checkFail
+)
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	checks := []func() (checkResult, string){
+		checkGit,
+		checkGh,
+		checkGhAuth,
+		checkWorktreeDir,
+		checkStaleWorktrees,
+	}
+
+	failed := 0
+	for _, check := range checks {
+		result, message := check()
+		switch result {
+		case checkOK:
+			Log.Outf(logger.Green, "✓ %s\n", message)
+		case checkSkipped:
+			Log.Outf(logger.Default, "- %s\n", message)
+		case checkWarn:
+			Log.Outf(logger.Yellow, "! %s\n", message)
+		default:
+			Log.Outf(logger.Red, "✗ %s\n", message)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	Log.Outf(logger.Green, "\nNo problems found.\n")
+	return nil
+}
+
+func checkGit() (checkResult, string) {
+	if _, err := exec.LookPath(git.Binary); err != nil {
+		return checkFail, fmt.Sprintf("%s not found on PATH", git.Binary)
+	}
+	version, err := git.Version()
+	if err != nil {
+		return checkFail, err.Error()
+	}
+	return versionCheck("git", version, git.MinVersion)
+}
+
+func checkGh() (checkResult, string) {
+	stdout, stderr, err := ghExec("--version")
+	if err != nil {
+		return checkFail, ghError("gh --version failed", err, stderr).Error()
+	}
+	version, err := git.ParseVersion(stdout.String())
+	if err != nil {
+		return checkWarn, "gh is installed but its version could not be determined"
+	}
+	return versionCheck("gh", version, minGhVersion)
+}
+
+func checkGhAuth() (checkResult, string) {
+	if _, stderr, err := ghExec("auth", "status"); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return checkFail, fmt.Sprintf("gh is not authenticated; run 'gh auth login'\n  %s", strings.ReplaceAll(msg, "\n", "\n  "))
+	}
+	return checkOK, "gh is authenticated"
+}
+
+func checkWorktreeDir() (checkResult, string) {
+	cfg, err := config.Get()
+	if err != nil {
+		return checkFail, fmt.Sprintf("invalid config: %v", err)
+	}
+
+	fi, err := os.Stat(cfg.WorktreeBase)
+	switch {
+	case os.IsNotExist(err):
+		return checkWarn, fmt.Sprintf("worktree directory %s does not exist yet; it is created with the first worktree", cfg.WorktreeBase)
+	case err != nil:
+		return checkFail, fmt.Sprintf("cannot access worktree directory: %v", err)
+	case !fi.IsDir():
+		return checkFail, fmt.Sprintf("worktree directory %s is not a directory", cfg.WorktreeBase)
+	}
+
+	// EnsureDir only creates missing directories, and this one exists, so this is just a write test.
+	if err := worktree.EnsureDir(cfg.WorktreeBase); err != nil {
+		return checkFail, err.Error()
+	}
+	return checkOK, fmt.Sprintf("worktree directory %s is writable", cfg.WorktreeBase)
+}
+
+func checkStaleWorktrees() (checkResult, string) {
+	if !git.IsGitRepository(".") {
+		return checkSkipped, "not in a git repository; skipped worktree checks"
+	}
+
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		return checkFail, fmt.Sprintf("failed to list worktrees: %v", err)
+	}
+
+	stale := 0
+	registered := make(map[string]bool, len(worktrees))
+	for _, wt := range worktrees {
+		registered[resolvePath(wt.Path)] = true
+		if !wt.Locked && !worktree.Exists(wt.Path) {
+			stale++
+		}
+	}
+
+	orphans := 0
+	if dirs, err := repoWorktreeDirs(); err == nil {
+		for _, dir := range dirs {
+			if !registered[resolvePath(dir)] {
+				orphans++
+			}
+		}
+	}
+
+	if stale > 0 || orphans > 0 {
+		return checkWarn, fmt.Sprintf("%d stale worktree record(s) and %d orphaned directory(ies); run 'gh wt prune'", stale, orphans)
+	}
+	return checkOK, fmt.Sprintf("%d worktree(s), none stale", len(worktrees))
+}
+
+// versionCheck compares the installed version of a tool with the oldest supported one.
+func versionCheck(name string, version, minVersion git.VersionNumber) (checkResult, string) {
+	if !version.AtLeast(minVersion) {
+		return checkFail, fmt.Sprintf("%s %s is too old; version %s or newer is required", name, version, minVersion)
+	}
+	return checkOK, fmt.Sprintf("%s %s", name, version)
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ffalor/gh-wt/internal/execext"
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/logger"
+	"github.com/ffalor/gh-wt/internal/shell"
+	"github.com/ffalor/gh-wt/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+// execCmd represents the exec command.
+var execCmd = &cobra.Command{
+	Use:   "exec -- <command>...",
+	Short: "Run a command in every worktree",
+	Long: `Run a command in every worktree of the current repository, including the main one.
+
+The command runs with the worktree as its working directory. Each line of output is
+prefixed with the worktree name. By default worktrees are processed one at a time and
+the first failure stops the remaining ones; use --continue-on-error to run them all.
+
+Examples:
+  # Pull in every worktree
+  gh wt exec -- git pull
+
+  # Install dependencies in four worktrees at a time
+  gh wt exec --jobs 4 --continue-on-error -- npm ci`,
+	Args: cobra.NoArgs,
+	RunE: runExec,
+}
+
+var (
+	execJobsFlag            int
+	execContinueOnErrorFlag bool
+)
+
+func init() {
+	execCmd.Flags().IntVarP(&execJobsFlag, "jobs", "j", 1, "number of worktrees to run the command in at once")
+	execCmd.Flags().BoolVar(&execContinueOnErrorFlag, "continue-on-error", false, "keep running in the remaining worktrees after a failure")
+	rootCmd.AddCommand(execCmd)
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	if !git.IsGitRepository(".") {
+		return fmt.Errorf("not in a git repository")
+	}
+	if len(cliArgv) == 0 {
+		return cmd.Help()
+	}
+	if execJobsFlag < 1 {
+		return fmt.Errorf("--jobs must be at least 1, got %d", execJobsFlag)
+	}
+
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		return err
+	}
+
+	// Bare repositories have no working tree and missing directories cannot be entered.
+	var targets []git.WorktreeInfo
+	for _, wt := range worktrees {
+		if !wt.Bare && worktree.Exists(wt.Path) {
+			targets = append(targets, wt)
+		}
+	}
+
+	// Workers take worktrees in git's order. After a failure, the remaining worktrees are
+	// skipped unless --continue-on-error is set; commands already running are not interrupted.
+	var (
+		mu                   sync.Mutex // serializes output lines across worktrees
+		wg                   sync.WaitGroup
+		stopped              atomic.Bool
+		ran, failed, skipped int
+	)
+	work := make(chan git.WorktreeInfo)
+	for range execJobsFlag {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for wt := range work {
+				if stopped.Load() {
+					mu.Lock()
+					skipped++
+					mu.Unlock()
+					continue
+				}
+
+				err := execInWorktree(wt.Path, &mu)
+
+				mu.Lock()
+				ran++
+				if err != nil {
+					failed++
+					Log.Errorf("Command failed in '%s': %v\n", wt.Path, err)
+					if !execContinueOnErrorFlag {
+						stopped.Store(true)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, wt := range targets {
+		work <- wt
+	}
+	close(work)
+	wg.Wait()
+
+	Log.Outf(logger.Green, "\nRan in %d worktree(s), %d failed, %d skipped.\n", ran, failed, skipped)
+	if failed > 0 {
+		return fmt.Errorf("command failed in %d worktree(s)", failed)
+	}
+	return nil
+}
+
+// execInWorktree runs cliArgv in dir, prefixing its output with the worktree name.
+func execInWorktree(dir string, mu *sync.Mutex) error {
+	prefix := filepath.Base(dir)
+	stdout := &prefixWriter{mu: mu, w: Log.Stdout, prefix: prefix}
+	stderr := &prefixWriter{mu: mu, w: Log.Stderr, prefix: prefix}
+	defer stdout.Flush()
+	defer stderr.Flush()
+
+	// Commands may run in parallel, so none of them gets the terminal's stdin.
+	return execext.RunCommand(context.Background(), &execext.RunCommandOptions{
+		Command: quoteArgs(cliArgv),
+		Dir:     dir,
+		Stdin:   strings.NewReader(""),
+		Stdout:  stdout,
+		Stderr:  stderr,
+	})
+}
+
+// quoteArgs joins args into a shell command that runs them as given, so that an argument
+// such as "fix bug" stays one argument.
+func quoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shell.Quote(shell.Bash, arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// prefixWriter writes complete lines to w, each prefixed with "[prefix] ".
+// Writers share mu so that lines from commands running in parallel do not interleave.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		fmt.Fprintf(p.w, "[%s] %s", p.prefix, p.buf[:i+1])
+		p.buf = p.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+// Flush writes a trailing partial line, if any.
+func (p *prefixWriter) Flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.buf) > 0 {
+		fmt.Fprintf(p.w, "[%s] %s\n", p.prefix, p.buf)
+		p.buf = nil
+	}
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExecKeepsArguments(t *testing.T) {
+	repo, _ := setupRepo(t)
+	linked := filepath.Join(filepath.Dir(repo), "linked")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "feature", linked)
+	setFlag(t, &cliArgv, []string{"git", "commit", "-q", "--allow-empty", "-m", "fix bug; it's done"})
+
+	if err := runExec(execCmd, nil); err != nil {
+		t.Fatalf("runExec() error = %v", err)
+	}
+	for _, dir := range []string{repo, linked} {
+		if got := runGit(t, dir, "log", "-1", "--format=%s"); got != "fix bug; it's done" {
+			t.Errorf("commit message in %s = %q, want %q", dir, got, "fix bug; it's done")
+		}
+	}
+}
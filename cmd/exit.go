@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of gh wt, so that scripts can tell kinds of failures apart.
+const (
+	ExitGeneric  = 1 // any failure without a more specific code
+	ExitUsage    = 2 // invalid flags or arguments
+	ExitConflict = 3 // the worktree, directory, or branch already exists or is in use
+	ExitNotFound = 4 // the worktree, ref, remote, PR, or issue does not exist
+	ExitAuth     = 5 // gh is not logged in or its token was rejected
+	ExitGit      = 6 // a git command failed
+)
+
+// exitError is an error that makes gh wt exit with a specific code.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode makes err exit gh wt with code. A nil err stays nil.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// exitCode returns the code gh wt exits with for err. Failed git commands that were not given a
+// more specific code exit with ExitGit.
+func exitCode(err error) int {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	var gitErr *git.Error
+	if errors.As(err, &gitErr) {
+		return ExitGit
+	}
+	return ExitGeneric
+}
+
+// markUsageErrors gives flag and argument errors of cmd and its subcommands the ExitUsage code.
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return withExitCode(ExitUsage, err)
+	})
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			return withExitCode(ExitUsage, validate(cmd, args))
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
+	}
+}
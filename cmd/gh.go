@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	gh "github.com/cli/go-gh/v2"
+	"github.com/ffalor/gh-wt/internal/git"
+)
+
+// ghExec runs gh with args, logging the command line at debug level.
+func ghExec(args ...string) (stdout, stderr bytes.Buffer, err error) {
+	Log.Debugf("+ %s\n", git.FormatCommand("gh", args))
+	return gh.Exec(args...)
+}
+
+// ghExecIn runs gh with args in dir, for commands such as gh pr checkout that act on the
+// repository in the current directory.
+func ghExecIn(ctx context.Context, dir string, args ...string) (stdout, stderr bytes.Buffer, err error) {
+	Log.Debugf("+ (in %s) %s\n", dir, git.FormatCommand("gh", args))
+	ghPath, err := gh.Path()
+	if err != nil {
+		return stdout, stderr, err
+	}
+	cmd := exec.CommandContext(ctx, ghPath, args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return stdout, stderr, fmt.Errorf("gh execution failed: %w", err)
+	}
+	return stdout, stderr, nil
+}
+
+// authErrorMarkers are found in gh's stderr when it is not logged in or its token is rejected.
+var authErrorMarkers = []string{
+	"gh auth login",
+	"http 401",
+	"bad credentials",
+	"authentication required",
+}
+
+// isGhAuthError reports whether stderr from gh indicates missing or invalid authentication.
+func isGhAuthError(stderr string) bool {
+	stderr = strings.ToLower(stderr)
+	for _, marker := range authErrorMarkers {
+		if strings.Contains(stderr, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// ghError turns a failed gh.Exec call into an error for action.
+// Authentication failures get a hint to run gh auth login; gh's own output is logged at debug level.
+// They exit with ExitAuth, and PRs, issues, or repositories that do not exist with ExitNotFound.
+func ghError(action string, err error, stderr bytes.Buffer) error {
+	msg := strings.TrimSpace(stderr.String())
+	if isGhAuthError(msg) {
+		Log.Debugf("gh: %s\n", msg)
+		return withExitCode(ExitAuth, fmt.Errorf("%s: not logged in to GitHub; run 'gh auth login' and try again", action))
+	}
+	if msg == "" {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	if strings.Contains(strings.ToLower(msg), "could not resolve to a") {
+		return withExitCode(ExitNotFound, fmt.Errorf("%s: %s", action, msg))
+	}
+	return fmt.Errorf("%s: %s", action, msg)
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ffalor/gh-wt/internal/config"
+	"github.com/ffalor/gh-wt/internal/git"
+)
+
+// setupRepo creates a repository named "r" with one commit, makes it the current directory, and
+// loads a config that puts worktrees under base (see setupConfig).
+func setupRepo(t *testing.T) (repo, base string) {
+	t.Helper()
+	root, base := setupConfig(t)
+	repo = filepath.Join(root, "r")
+	if err := os.MkdirAll(repo, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "init", "-q", "-b", "main")
+	runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "init")
+	t.Chdir(repo)
+	return repo, base
+}
+
+// setupConfig loads a config that puts worktrees under base, in a new temporary directory root.
+// HOME and git's global config point at empty temporary files so that the user's setup does not
+// leak into tests.
+func setupConfig(t *testing.T) (root, base string) {
+	t.Helper()
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	home, base := filepath.Join(root, "home"), filepath.Join(root, "wt")
+
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(home, ".gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "test")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@example.com")
+	}
+	writeTestFile(t, filepath.Join(home, ".config", "gh-wt", "config.yaml"), "worktree_dir: "+base+"\n")
+	if _, err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+	return root, base
+}
+
+// runGit runs git with args in dir and returns its trimmed output, failing the test if it fails.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setFlag sets a command-line flag variable for the duration of the test.
+func setFlag[T any](t *testing.T, flag *T, value T) {
+	t.Helper()
+	old := *flag
+	*flag = value
+	t.Cleanup(func() { *flag = old })
+}
+
+// stubPrompts replaces the terminal check and confirmation prompts for the duration of the test.
+// Every prompt is answered with answer, and the questions asked are returned.
+func stubPrompts(t *testing.T, terminal, answer bool) *[]string {
+	t.Helper()
+	var asked []string
+	setFlag(t, &stdinIsTerminal, func() bool { return terminal })
+	setFlag(t, &confirm, func(message string, _ bool) (bool, error) {
+		asked = append(asked, message)
+		return answer, nil
+	})
+	return &asked
+}
+
+// worktreeAt returns the registered worktree at path.
+func worktreeAt(t *testing.T, path string) git.WorktreeInfo {
+	t.Helper()
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, wt := range worktrees {
+		if wt.Path == path {
+			return wt
+		}
+	}
+	t.Fatalf("no worktree registered at %s", path)
+	return git.WorktreeInfo{}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// fakeGh makes gh commands run a shell script instead of gh, through the GH_PATH variable that
+// go-gh honours. The script gets gh's arguments, and the arguments of every call are appended to
+// the returned log file, one call per line.
+func fakeGh(t *testing.T, script string) (log string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake gh is a shell script")
+	}
+	dir := t.TempDir()
+	log = filepath.Join(dir, "calls.log")
+	path := filepath.Join(dir, "gh")
+	content := "#!/bin/sh\necho \"$*\" >> '" + log + "'\n" + script + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GH_PATH", path)
+	return log
+}
+
+// ghCalls returns the arguments of the calls to the fake gh logged to log.
+func ghCalls(t *testing.T, log string) []string {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
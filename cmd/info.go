@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+// infoCmd represents the info command.
+var infoCmd = &cobra.Command{
+	Use:   "info <name|number|url>",
+	Short: "Show details about one worktree",
+	Long: `Show details about one worktree: its path, branch, upstream, commits ahead of and
+behind the upstream, uncommitted changes, lock status, and the PR or issue it was created for.
+The PR or issue title and state are looked up with gh.
+
+The worktree can be given by name, PR or issue number, or PR or issue URL.
+Use --json for output that scripts can parse.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeWorktreeNames,
+	RunE:              runInfo,
+}
+
+var infoJSONFlag bool
+
+func init() {
+	infoCmd.Flags().BoolVar(&infoJSONFlag, "json", false, "print the details as JSON")
+	rootCmd.AddCommand(infoCmd)
+}
+
+// worktreeDetails is what info prints about a worktree.
+type worktreeDetails struct {
+	Path       string `json:"path"`
+	Branch     string `json:"branch,omitempty"`
+	Head       string `json:"head"`
+	Detached   bool   `json:"detached"`
+	Upstream   string `json:"upstream,omitempty"`
+	Ahead      int    `json:"ahead"`
+	Behind     int    `json:"behind"`
+	Dirty      bool   `json:"dirty"`
+	Locked     bool   `json:"locked"`
+	LockReason string `json:"lockReason,omitempty"`
+
+	// From the worktree's metadata, and gh for the title, state, and URL.
+	Type      worktree.WorktreeType `json:"type,omitempty"`
+	Number    int                   `json:"number,omitempty"`
+	Title     string                `json:"title,omitempty"`
+	State     string                `json:"state,omitempty"`
+	URL       string                `json:"url,omitempty"`
+	CreatedAt *time.Time            `json:"createdAt,omitempty"`
+}
+
+func runInfo(cmd *cobra.Command, args []string) error {
+	if !git.IsGitRepository(".") {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	// Showing details changes nothing, so a fuzzy match needs no confirmation.
+	target, fuzzy, err := lookupWorktree(args[0])
+	if err != nil {
+		return err
+	}
+	if fuzzy {
+		logFuzzyMatch(args[0], []string{target.Path})
+	}
+	if target == nil {
+		// lookupWorktree already said so; fail so that scripts notice.
+		return withExitCode(ExitNotFound, errors.New("worktree not found"))
+	}
+
+	details := collectDetails(*target)
+	if infoJSONFlag {
+		data, err := json.MarshalIndent(details, "", "  ")
+		if err != nil {
+			return err
+		}
+		Log.Plainf("%s\n", data)
+		return nil
+	}
+	return printDetails(details)
+}
+
+// collectDetails gathers the details of wt. Details that cannot be determined are left empty.
+func collectDetails(wt git.WorktreeInfo) worktreeDetails {
+	details := worktreeDetails{
+		Path:       wt.Path,
+		Branch:     wt.Branch,
+		Head:       wt.Head,
+		Detached:   wt.Detached,
+		Dirty:      git.HasUncommittedChanges(wt.Path),
+		Locked:     wt.Locked,
+		LockReason: wt.LockReason,
+	}
+
+	if upstream, err := git.Upstream(wt.Path); err == nil {
+		details.Upstream = upstream
+		if details.Ahead, details.Behind, err = git.AheadBehind(wt.Path); err != nil {
+			Log.Debugf("Failed to compare %s with its upstream: %v\n", wt.Path, err)
+		}
+	}
+
+	md, err := worktree.ReadMetadata(wt.Path)
+	if err != nil {
+		Log.Warnf("⚠️  %v\n", err)
+	}
+	if md == nil {
+		return details
+	}
+	details.Type = md.Type
+	details.Number = md.Number
+	if !md.CreatedAt.IsZero() {
+		details.CreatedAt = &md.CreatedAt
+	}
+
+	if md.Number > 0 && (md.Type == worktree.PR || md.Type == worktree.Issue) {
+		kind := "pr"
+		if md.Type == worktree.Issue {
+			kind = "issue"
+		}
+		args := []string{kind, "view", strconv.Itoa(md.Number), "--json", "title,state,url"}
+		if md.Owner != "" && md.Repo != "" {
+			args = append(args, "--repo", md.Owner+"/"+md.Repo)
+		}
+		stdout, stderr, err := ghExec(args...)
+		if err != nil {
+			Log.Warnf("⚠️  %v\n", ghError(fmt.Sprintf("failed to fetch %s #%d", kind, md.Number), err, stderr))
+			return details
+		}
+		var item struct {
+			Title string `json:"title"`
+			State string `json:"state"`
+			URL   string `json:"url"`
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &item); err != nil {
+			Log.Warnf("⚠️  Failed to parse %s #%d: %v\n", kind, md.Number, err)
+			return details
+		}
+		details.Title, details.State, details.URL = item.Title, item.State, item.URL
+	}
+	return details
+}
+
+// printDetails prints details as aligned "Key: value" lines.
+func printDetails(d worktreeDetails) error {
+	w := tabwriter.NewWriter(Log.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Path:\t%s\n", d.Path)
+
+	branch := d.Branch
+	if d.Detached {
+		branch = "(detached)"
+	}
+	fmt.Fprintf(w, "Branch:\t%s\n", branch)
+	fmt.Fprintf(w, "Head:\t%s\n", d.Head)
+
+	if d.Upstream != "" {
+		fmt.Fprintf(w, "Upstream:\t%s (+%d/-%d)\n", d.Upstream, d.Ahead, d.Behind)
+	} else {
+		fmt.Fprintf(w, "Upstream:\t-\n")
+	}
+
+	state := "clean"
+	if d.Dirty {
+		state = "dirty"
+	}
+	fmt.Fprintf(w, "State:\t%s\n", state)
+
+	locked := "no"
+	switch {
+	case d.Locked && d.LockReason != "":
+		locked = "yes (" + d.LockReason + ")"
+	case d.Locked:
+		locked = "yes"
+	}
+	fmt.Fprintf(w, "Locked:\t%s\n", locked)
+
+	if d.Type != "" {
+		fmt.Fprintf(w, "Type:\t%s\n", d.Type)
+	}
+	if d.Number > 0 {
+		line := "#" + strconv.Itoa(d.Number)
+		if d.Title != "" {
+			line += " " + d.Title
+		}
+		if d.State != "" {
+			line += " (" + d.State + ")"
+		}
+		fmt.Fprintf(w, "Number:\t%s\n", line)
+	}
+	if d.URL != "" {
+		fmt.Fprintf(w, "URL:\t%s\n", d.URL)
+	}
+	if d.CreatedAt != nil {
+		fmt.Fprintf(w, "Created:\t%s\n", d.CreatedAt.Local().Format(time.DateTime))
+	}
+	return w.Flush()
+}
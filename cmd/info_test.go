@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInfoAmbiguousWithoutTerminal(t *testing.T) {
+	repo, base := setupRepo(t)
+	pr, issue := filepath.Join(base, "r", "pr_1"), filepath.Join(base, "r", "issue_1")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "pr_1", pr)
+	runGit(t, repo, "worktree", "add", "-q", "-b", "issue_1", issue)
+	stubPrompts(t, false, true)
+	setFlag(t, &infoJSONFlag, true)
+
+	err := runInfo(infoCmd, []string{"1"})
+	if code := exitCode(err); code != ExitUsage {
+		t.Fatalf("runInfo() error = %v (exit code %d), want exit code %d", err, code, ExitUsage)
+	}
+	for _, path := range []string{pr, issue} {
+		if !strings.Contains(err.Error(), path) {
+			t.Errorf("error %q does not list %s", err, path)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+// lockCmd represents the lock command.
+var lockCmd = &cobra.Command{
+	Use:   "lock <worktree-name>",
+	Short: "Lock a worktree to prevent it from being pruned, moved, or removed",
+	Long: `Lock a worktree to prevent it from being pruned, moved, or removed.
+This is useful for worktrees on removable media or network shares that are not always available.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeWorktreeNames,
+	RunE:              runLock,
+}
+
+// unlockCmd represents the unlock command.
+var unlockCmd = &cobra.Command{
+	Use:               "unlock <worktree-name>",
+	Short:             "Unlock a locked worktree",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeWorktreeNames,
+	RunE:              runUnlock,
+}
+
+var lockReasonFlag string
+
+func init() {
+	lockCmd.Flags().StringVar(&lockReasonFlag, "reason", "", "reason for locking the worktree")
+	rootCmd.AddCommand(lockCmd, unlockCmd)
+}
+
+func runLock(cmd *cobra.Command, args []string) error {
+	if !git.IsGitRepository(".") {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	target, err := selectWorktree(args[0])
+	if err != nil || target == nil {
+		return err
+	}
+
+	if target.Locked {
+		Log.Warnf("Worktree '%s' is already locked.\n", target.Path)
+		return nil
+	}
+
+	if err := git.WorktreeLock(target.Path, lockReasonFlag); err != nil {
+		return fmt.Errorf("failed to lock worktree: %w", err)
+	}
+	Log.Outf(logger.Green, "Locked worktree '%s'.\n", target.Path)
+	return nil
+}
+
+func runUnlock(cmd *cobra.Command, args []string) error {
+	if !git.IsGitRepository(".") {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	target, err := selectWorktree(args[0])
+	if err != nil || target == nil {
+		return err
+	}
+
+	if !target.Locked {
+		Log.Warnf("Worktree '%s' is not locked.\n", target.Path)
+		return nil
+	}
+
+	if err := git.WorktreeUnlock(target.Path); err != nil {
+		return fmt.Errorf("failed to unlock worktree: %w", err)
+	}
+	Log.Outf(logger.Green, "Unlocked worktree '%s'.\n", target.Path)
+	return nil
+}
+
+// lockedError explains that a worktree is locked and how to unlock it.
+func lockedError(wt git.WorktreeInfo) error {
+	msg := fmt.Sprintf("worktree '%s' is locked", wt.Path)
+	if wt.LockReason != "" {
+		msg += fmt.Sprintf(" (%s)", wt.LockReason)
+	}
+	return fmt.Errorf("%s; run 'gh wt unlock %s' first", msg, filepath.Base(wt.Path))
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+// moveCmd represents the move command.
+var moveCmd = &cobra.Command{
+	Use:   "move <worktree-name> <new-path>",
+	Short: "Move a worktree to a new location",
+	Long: `Move a worktree to a new location.
+
+The branch of the worktree is kept as is. The destination must not exist and
+must not be inside another worktree. Locked worktrees must be unlocked first.`,
+	Aliases: []string{"mv"},
+	Args:    cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		}
+		return completeWorktreeNames(cmd, args, toComplete)
+	},
+	RunE: runMove,
+}
+
+func init() {
+	rootCmd.AddCommand(moveCmd)
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	if !git.IsGitRepository(".") {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	target, err := selectWorktree(args[0])
+	if err != nil || target == nil {
+		return err
+	}
+
+	mainPath, err := git.GetMainWorktreePath()
+	if err != nil {
+		return err
+	}
+	if resolvePath(target.Path) == resolvePath(mainPath) {
+		return fmt.Errorf("cannot move the main worktree")
+	}
+
+	if target.Locked {
+		return lockedError(*target)
+	}
+
+	newPath, err := filepath.Abs(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid path '%s': %w", args[1], err)
+	}
+	if _, err := os.Lstat(newPath); err == nil {
+		return fmt.Errorf("destination '%s' already exists", newPath)
+	}
+
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		return err
+	}
+	if owner := containingWorktree(worktrees, newPath); owner != "" {
+		return fmt.Errorf("destination '%s' is inside worktree '%s'", newPath, owner)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0o755); err != nil {
+		return fmt.Errorf("cannot create parent directory: %w", err)
+	}
+
+	ctx, cancel := gitContext()
+	defer cancel()
+	Log.Infof("Moving worktree '%s' to '%s'...\n", target.Path, newPath)
+	if err := git.WorktreeMove(ctx, target.Path, newPath); err != nil {
+		return fmt.Errorf("failed to move worktree: %w", err)
+	}
+
+	Log.Outf(logger.Green, "\nWorktree moved successfully!\n")
+	Log.Outf(logger.Default, "Location: %s\n", newPath)
+	return nil
+}
+
+// containingWorktree returns the path of the worktree that contains path, or "" if there is none.
+func containingWorktree(worktrees []git.WorktreeInfo, path string) string {
+	// The destination does not exist yet, so resolve its parent to compare against git's paths.
+	resolved := filepath.Join(resolvePath(filepath.Dir(path)), filepath.Base(path))
+	for _, wt := range worktrees {
+		wtPath := resolvePath(wt.Path)
+		if resolved == wtPath || strings.HasPrefix(resolved, wtPath+string(filepath.Separator)) {
+			return wt.Path
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/cli/go-gh/v2/pkg/prompter"
+	"github.com/ffalor/gh-wt/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// openCmd represents the open command.
+var openCmd = &cobra.Command{
+	Use:   "open [name|number|url]",
+	Short: "Open a worktree in your editor",
+	Long: `Open a worktree in your editor.
+
+A worktree can be referenced by its name, a PR or issue number, or a PR or issue URL.
+If the worktree does not exist yet, you are offered to create it first.
+Without an argument, pick one of the recently used worktrees (see gh wt recent).
+
+Examples:
+  gh wt open pr_123
+  gh wt open https://github.com/owner/repo/pull/123
+  gh wt open my-feature-branch`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeWorktreeNames,
+	RunE:              runOpen,
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Get()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		path, err := pickRecent()
+		if err != nil {
+			return err
+		}
+		recordRecent(path)
+		openInEditor(cfg.Editor, path)
+		return nil
+	}
+	input := args[0]
+
+	matches, err := matchWorktreePaths(input)
+	if err != nil {
+		return err
+	}
+
+	p := prompter.New(os.Stdin, os.Stdout, os.Stderr)
+
+	switch len(matches) {
+	case 0:
+		if !forceFlag {
+			create, err := p.Confirm(fmt.Sprintf("Worktree '%s' does not exist. Create it?", input), true)
+			if err != nil {
+				return fmt.Errorf("prompt failed: %w", err)
+			}
+			if !create {
+				Log.Warnf("Cancelled - no changes made\n")
+				return nil
+			}
+		}
+
+		// createWorktree opens the editor once the worktree exists.
+		openFlag = true
+		if _, err := strconv.Atoi(input); err == nil {
+			return createFromPR(input)
+		}
+		return createFromArg(input)
+	case 1:
+		recordRecent(matches[0])
+		openInEditor(cfg.Editor, matches[0])
+	default:
+		idx, err := p.Select("Multiple worktrees match '"+input+"'. Select one:", "", matches)
+		if err != nil {
+			return fmt.Errorf("prompt failed: %w", err)
+		}
+		recordRecent(matches[idx])
+		openInEditor(cfg.Editor, matches[idx])
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/prompter"
+	"github.com/cli/go-gh/v2/pkg/term"
+)
+
+// stdinIsTerminal reports whether questions can be asked on stdin. Tests replace it.
+var stdinIsTerminal = func() bool {
+	return term.IsTerminal(os.Stdin)
+}
+
+// confirm asks a yes or no question. Tests replace it.
+var confirm = func(message string, defaultValue bool) (bool, error) {
+	return prompter.New(os.Stdin, os.Stdout, os.Stderr).Confirm(message, defaultValue)
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ffalor/gh-wt/internal/config"
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/logger"
+	"github.com/ffalor/gh-wt/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+// pruneCmd represents the prune command.
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Clean up stale worktree records and orphaned directories",
+	Long: `Prune stale worktree records from git and delete directories in the worktree
+directory that are no longer registered as worktrees of the current repository.
+
+Orphaned directories are only deleted after confirmation (unless --force is used).`,
+	Args: cobra.NoArgs,
+	RunE: runPrune,
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	if !git.IsGitRepository(".") {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		return err
+	}
+
+	// 1. Prune records of worktrees that no longer exist on disk.
+	// git keeps records of locked worktrees even if their directory is missing.
+	staleRecords, lockedRecords := 0, 0
+	for _, wt := range worktrees {
+		if worktree.Exists(wt.Path) {
+			continue
+		}
+		if wt.Locked {
+			lockedRecords++
+			continue
+		}
+		staleRecords++
+	}
+
+	ctx, cancel := gitContext()
+	defer cancel()
+	Log.Infof("Pruning stale worktree records...\n")
+	if err := git.WorktreePrune(ctx); err != nil {
+		return fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+
+	// 2. Find directories in the worktree base that git does not know about.
+	dirs, err := repoWorktreeDirs()
+	if err != nil {
+		return err
+	}
+
+	registered := make(map[string]bool, len(worktrees))
+	for _, wt := range worktrees {
+		registered[resolvePath(wt.Path)] = true
+	}
+
+	commonDir, err := git.GetGitCommonDir(".")
+	if err != nil {
+		return err
+	}
+
+	var orphans []string
+	for _, dir := range dirs {
+		if registered[resolvePath(dir)] {
+			continue
+		}
+		// Repositories with the same name share the directory under the default {repo}/{name}
+		// layout, so worktrees and clones of other repositories there are not orphans.
+		if !ownedBy(dir, commonDir) {
+			Log.Debugf("Skipping %s: it belongs to another repository\n", dir)
+			continue
+		}
+		orphans = append(orphans, dir)
+	}
+
+	removedOrphans := 0
+	if len(orphans) > 0 {
+		confirmed := forceFlag
+		if !confirmed {
+			if !stdinIsTerminal() {
+				return fmt.Errorf("found %d orphaned directory(ies) that are not registered worktrees; use --force to delete them without confirmation", len(orphans))
+			}
+			var message strings.Builder
+			message.WriteString("Found directories that are not registered worktrees:\n")
+			for _, dir := range orphans {
+				message.WriteString("- ")
+				message.WriteString(dir)
+				message.WriteString("\n")
+			}
+			message.WriteString("\nDelete them?")
+
+			confirmed, err = confirm(message.String(), false)
+			if err != nil {
+				return fmt.Errorf("prompt failed: %w", err)
+			}
+		}
+
+		if confirmed {
+			for _, dir := range orphans {
+				if err := os.RemoveAll(dir); err != nil {
+					Log.Warnf("Failed to remove %s: %v\n", dir, err)
+					continue
+				}
+				removedOrphans++
+			}
+		} else {
+			Log.Warnf("Skipped deleting orphaned directories.\n")
+		}
+	}
+
+	if lockedRecords > 0 {
+		Log.Warnf("Kept %d locked worktree record(s). Use 'gh wt unlock <name>' to allow pruning them.\n", lockedRecords)
+	}
+	Log.Outf(logger.Green, "\nPruned %d stale worktree record(s) and %d orphaned directory(ies).\n", staleRecords, removedOrphans)
+	return nil
+}
+
+// repoWorktreeDirs returns the directories in the worktree base that belong to the current repository
+// according to the configured path template.
+func repoWorktreeDirs() ([]string, error) {
+	cfg, err := config.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	repoName, err := git.GetRepoName()
+	if err != nil {
+		return nil, err
+	}
+
+	baseDir, pathTemplate := worktreeLayout(cfg)
+	return worktreeDirs(baseDir, pathTemplate, repoName)
+}
+
+// worktreeDirs returns the directories under baseDir that are worktrees of repo according to
+// pathTemplate. repo may be worktree.Wildcard to find the worktrees of every repository.
+func worktreeDirs(baseDir, pathTemplate, repo string) ([]string, error) {
+	path, err := worktree.RenderPath(baseDir, pathTemplate, &worktree.WorktreeInfo{
+		Owner:        worktree.Wildcard,
+		Repo:         repo,
+		BranchName:   worktree.Wildcard,
+		WorktreeName: worktree.Wildcard,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := filepath.Glob(worktree.GlobPattern(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search worktree directory: %w", err)
+	}
+
+	var dirs []string
+	for _, match := range matches {
+		if filepath.Base(match) == git.BareDirName {
+			continue
+		}
+		if fi, err := os.Stat(match); err == nil && fi.IsDir() {
+			dirs = append(dirs, match)
+		}
+	}
+	return dirs, nil
+}
+
+// resolvePath returns an absolute path with symlinks resolved, for comparing against git's paths.
+func resolvePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return path
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPruneOrphanedDirectories(t *testing.T) {
+	tests := []struct {
+		name     string
+		terminal bool
+		force    bool
+		wantErr  bool
+		deleted  bool
+	}{
+		{"no terminal", false, false, true, false},
+		{"no terminal with --force", false, true, false, true},
+		{"confirmed", true, false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, base := setupRepo(t)
+			orphan := filepath.Join(base, "r", "leftover")
+			writeTestFile(t, filepath.Join(orphan, "file.txt"), "left behind")
+			asked := stubPrompts(t, tt.terminal, true)
+			setFlag(t, &forceFlag, tt.force)
+
+			err := runPrune(pruneCmd, nil)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("runPrune() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "use --force") {
+				t.Errorf("runPrune() error = %v, want a --force hint", err)
+			}
+			if deleted := !exists(orphan); deleted != tt.deleted {
+				t.Errorf("deleted = %v, want %v", deleted, tt.deleted)
+			}
+			if wantAsked := tt.terminal && !tt.force; (len(*asked) == 1) != wantAsked {
+				t.Errorf("asked %q", *asked)
+			}
+		})
+	}
+}
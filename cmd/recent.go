@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/prompter"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/ffalor/gh-wt/internal/config"
+	"github.com/ffalor/gh-wt/internal/recent"
+	"github.com/ffalor/gh-wt/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+// recentCmd represents the recent command.
+var recentCmd = &cobra.Command{
+	Use:   "recent",
+	Short: "Pick one of the recently used worktrees",
+	Long: `Pick one of the worktrees you recently created, opened, or switched to, across all
+repositories, and print its path so the shell can change into it:
+
+  cd "$(gh wt recent)"
+
+With --list, or without a terminal, the recent worktrees are listed instead, most recent first.
+Worktrees that no longer exist are left out.`,
+	Args: cobra.NoArgs,
+	RunE: runRecent,
+}
+
+var recentListFlag bool
+
+func init() {
+	recentCmd.Flags().BoolVar(&recentListFlag, "list", false, "list the recent worktrees instead of picking one")
+	rootCmd.AddCommand(recentCmd)
+}
+
+func runRecent(cmd *cobra.Command, args []string) error {
+	if recentListFlag || !term.IsTerminal(os.Stdin) {
+		entries, err := recentWorktrees()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(Log.Stdout, 0, 0, 2, ' ', 0)
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s ago\n", e.Path, formatAge(time.Since(e.UsedAt)))
+		}
+		return w.Flush()
+	}
+
+	path, err := pickRecent()
+	if err != nil {
+		return err
+	}
+	recordRecent(path)
+	Log.Plainf("%s\n", path)
+	return nil
+}
+
+// recentWorktrees returns the recently used worktrees that still exist, most recent first.
+func recentWorktrees() ([]recent.Entry, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := recent.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := entries[:0]
+	for _, e := range entries {
+		if worktree.Exists(e.Path) {
+			existing = append(existing, e)
+		}
+	}
+	return existing, nil
+}
+
+// pickRecent lets the user select one of the recently used worktrees and returns its path.
+// The prompt is written to stderr so that command substitution only captures the path.
+func pickRecent() (string, error) {
+	entries, err := recentWorktrees()
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("no recently used worktrees")
+	}
+	if !term.IsTerminal(os.Stdin) {
+		return "", fmt.Errorf("cannot pick a recent worktree without a terminal; use gh wt recent --list")
+	}
+
+	options := make([]string, len(entries))
+	for i, e := range entries {
+		options[i] = fmt.Sprintf("%s (%s ago)", e.Path, formatAge(time.Since(e.UsedAt)))
+	}
+	p := prompter.New(os.Stdin, os.Stderr, os.Stderr)
+	idx, err := p.Select("Select a recent worktree:", "", options)
+	if err != nil {
+		return "", fmt.Errorf("prompt failed: %w", err)
+	}
+	return entries[idx].Path, nil
+}
+
+// recordRecent adds path to the recently used worktrees. Failures are only logged at debug
+// level, since the history is a convenience.
+func recordRecent(path string) {
+	dir, err := config.Dir()
+	if err == nil {
+		err = recent.Record(dir, path)
+	}
+	if err != nil {
+		Log.Debugf("Failed to record recent worktree %s: %v\n", path, err)
+	}
+}
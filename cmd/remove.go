@@ -1,99 +1,540 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/cli/go-gh/v2/pkg/prompter"
+	"github.com/cli/go-gh/v2/pkg/term"
 	"github.com/ffalor/gh-wt/internal/git"
 	"github.com/ffalor/gh-wt/internal/logger"
 	"github.com/ffalor/gh-wt/internal/worktree"
+	"github.com/ffalor/gh-wt/pkg/ghwt"
 	"github.com/spf13/cobra"
 )
 
 // rmCmd represents the rm command.
 var rmCmd = &cobra.Command{
-	Use:     "rm [worktree-name]",
-	Short:   "Remove a worktree and its associated branch",
-	Long:    `Remove a worktree and its associated branch. Will prompt if there are uncommitted changes (unless --force is used).`,
-	Aliases: []string{"remove"},
-	Args:    cobra.ExactArgs(1),
-	RunE:    runRm,
+	Use:   "rm [name|number|url|pattern]",
+	Short: "Remove a worktree and its associated branch",
+	Long: `Remove a worktree and its associated branch. Will prompt if there are uncommitted changes (unless --force is used).
+The worktree can be given by name, PR or issue number, or PR or issue URL.
+Branches with unmerged commits are only deleted with --force, and --keep-branch keeps the
+branch.
+
+A glob pattern, such as 'issue_*', removes every matching worktree of the current repository
+after confirming the list. Quote it so that the shell does not expand it.
+
+Use --all to remove every worktree of the current repository except the main one.
+Use --merged to remove PR worktrees whose pull request has been merged or closed.`,
+	Aliases:           []string{"remove"},
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeWorktreeNames,
+	RunE:              runRm,
 }
 
+var (
+	removeAllFlag    bool
+	removeMergedFlag bool
+	keepBranchFlag   bool
+)
+
 func init() {
+	rmCmd.Flags().BoolVarP(&removeAllFlag, "all", "a", false, "remove all worktrees of the current repository")
+	rmCmd.Flags().BoolVar(&removeMergedFlag, "merged", false, "remove PR worktrees whose pull request is merged or closed")
+	rmCmd.Flags().BoolVar(&keepBranchFlag, "keep-branch", false, "keep the worktree's branch instead of deleting it")
+	rmCmd.MarkFlagsMutuallyExclusive("all", "merged")
 	rootCmd.AddCommand(rmCmd)
 }
 
 func runRm(cmd *cobra.Command, args []string) error {
-	worktreeName := args[0]
-
 	// Require being in a git repository (consistent with create command)
 	if !git.IsGitRepository(".") {
 		return fmt.Errorf("not in a git repository")
 	}
 
-	// Find the worktree by name using the shared helper
-	matches, err := worktree.FindByName(worktreeName)
+	if removeAllFlag {
+		if len(args) > 0 {
+			return withExitCode(ExitUsage, fmt.Errorf("cannot use --all with a worktree name"))
+		}
+		return removeAllWorktrees()
+	}
+
+	if removeMergedFlag {
+		if len(args) > 0 {
+			return withExitCode(ExitUsage, fmt.Errorf("cannot use --merged with a worktree name"))
+		}
+		return removeMergedWorktrees()
+	}
+
+	if len(args) == 0 {
+		return cmd.Help()
+	}
+	worktreeName := args[0]
+
+	if isPattern(worktreeName) {
+		return removeMatchingWorktrees(worktreeName)
+	}
+
+	targetWorktree, err := selectWorktree(worktreeName)
 	if err != nil {
 		return err
 	}
+	if targetWorktree == nil {
+		// selectWorktree already said so; fail so that scripts notice.
+		return withExitCode(ExitNotFound, errors.New("worktree not found"))
+	}
+
+	_, err = removeWorktree(*targetWorktree, rmOptions())
+	return err
+}
+
+// selectWorktree finds a worktree to change or remove by name, PR or issue number, or PR or issue
+// URL, like lookupWorktree. A worktree picked by fuzzy matching is always confirmed, even with
+// --force, since it may not be the one the user meant; without a terminal it is not used.
+func selectWorktree(name string) (*git.WorktreeInfo, error) {
+	wt, fuzzy, err := lookupWorktree(name)
+	if err != nil || wt == nil || !fuzzy {
+		return wt, err
+	}
+
+	notFound := withExitCode(ExitNotFound, fmt.Errorf("worktree '%s' not found", name))
+	if !stdinIsTerminal() {
+		Log.Warnf("No worktree is named '%s'; the closest match is '%s'. Use its full name.\n", name, filepath.Base(wt.Path))
+		return nil, notFound
+	}
+	ok, err := confirm(fmt.Sprintf("No worktree is named '%s'. Use '%s'?", name, wt.Path), false)
+	if err != nil {
+		return nil, fmt.Errorf("prompt failed: %w", err)
+	}
+	if !ok {
+		return nil, notFound
+	}
+	return wt, nil
+}
+
+// lookupWorktree finds a worktree by name, PR or issue number, or PR or issue URL, prompting if
+// several match, or failing without a terminal. fuzzy reports whether a single fuzzy match was
+// picked without asking. It warns and returns nil if no worktree matches.
+func lookupWorktree(name string) (wt *git.WorktreeInfo, fuzzy bool, err error) {
+	matches, fuzzy, err := findRegisteredWorktrees(name)
+	if err != nil {
+		return nil, false, err
+	}
 
 	if len(matches) == 0 {
-		Log.Warnf("Worktree '%s' not found in this repository.\n", worktreeName)
-		return nil
+		Log.Warnf("Worktree '%s' not found in this repository.\n", name)
+		return nil, false, nil
 	}
 
-	// If multiple matches, prompt user to select one
-	var targetWorktree git.WorktreeInfo
 	if len(matches) == 1 {
-		targetWorktree = matches[0]
-	} else {
-		options := make([]string, len(matches))
-		for i, wt := range matches {
-			options[i] = wt.Path
+		return &matches[0], fuzzy, nil
+	}
+
+	options := make([]string, len(matches))
+	for i, wt := range matches {
+		options[i] = wt.Path
+	}
+	if !stdinIsTerminal() {
+		return nil, false, withExitCode(ExitUsage, fmt.Errorf("'%s' matches %d worktrees; name one of them more precisely:\n    %s", name, len(matches), strings.Join(options, "\n    ")))
+	}
+	p := prompter.New(os.Stdin, os.Stdout, os.Stderr)
+	idx, err := p.Select("Multiple worktrees match '"+name+"'. Select one:", "", options)
+	if err != nil {
+		return nil, false, fmt.Errorf("prompt failed: %w", err)
+	}
+	return &matches[idx], false, nil
+}
+
+// findRegisteredWorktrees resolves input like add does (see findWorktreePaths) and returns the
+// matching worktrees of the current repository. It falls back to matching the end of worktree paths,
+// then to fuzzy matching worktree names, which fuzzy reports.
+func findRegisteredWorktrees(input string) (matches []git.WorktreeInfo, fuzzy bool, err error) {
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		return nil, false, err
+	}
+
+	paths, err := findWorktreePaths(input)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, path := range paths {
+		for _, wt := range worktrees {
+			if resolvePath(wt.Path) == resolvePath(path) {
+				matches = append(matches, wt)
+			}
+		}
+	}
+	if len(matches) > 0 {
+		return matches, false, nil
+	}
+
+	if matches, err = worktree.FindByName(input); err != nil || len(matches) > 0 {
+		return matches, false, err
+	}
+	matches = fuzzyRegisteredWorktrees(input, worktrees)
+	return matches, len(matches) > 0, nil
+}
+
+// fuzzyRegisteredWorktrees returns the worktrees other than the main worktree whose directory
+// names best match input (see worktree.MatchScore).
+func fuzzyRegisteredWorktrees(input string, worktrees []git.WorktreeInfo) []git.WorktreeInfo {
+	// The first entry is always the main worktree.
+	if !isFuzzyQuery(input) || len(worktrees) <= 1 {
+		return nil
+	}
+	worktrees = worktrees[1:]
+
+	names := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		names[i] = filepath.Base(wt.Path)
+	}
+
+	var matches []git.WorktreeInfo
+	for _, i := range worktree.BestMatches(input, names) {
+		matches = append(matches, worktrees[i])
+	}
+	return matches
+}
+
+// removeAllWorktrees removes every worktree of the current repository except the main worktree.
+func removeAllWorktrees() error {
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		return err
+	}
+
+	// The first entry is always the main worktree.
+	if len(worktrees) <= 1 {
+		Log.Warnf("No worktrees to remove.\n")
+		return nil
+	}
+	return removeWorktrees(worktrees[1:])
+}
+
+// isPattern reports whether name is a glob pattern rather than a worktree name.
+func isPattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// removeMatchingWorktrees removes the worktrees of the current repository, other than the main
+// worktree, whose directory names match the glob pattern. The list is confirmed first unless
+// --force is given.
+func removeMatchingWorktrees(pattern string) error {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return withExitCode(ExitUsage, fmt.Errorf("invalid pattern '%s': %w", pattern, err))
+	}
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		return err
+	}
+
+	// The first entry is always the main worktree.
+	var matches []git.WorktreeInfo
+	for _, wt := range worktrees[min(1, len(worktrees)):] {
+		if ok, _ := filepath.Match(pattern, filepath.Base(wt.Path)); ok {
+			matches = append(matches, wt)
+		}
+	}
+	if len(matches) == 0 {
+		Log.Warnf("No worktrees match '%s'.\n", pattern)
+		return nil
+	}
+
+	if !forceFlag {
+		if !term.IsTerminal(os.Stdin) {
+			return fmt.Errorf("%d worktree(s) match '%s'; use --force to remove them without confirmation", len(matches), pattern)
+		}
+		var message strings.Builder
+		fmt.Fprintf(&message, "%d worktree(s) match '%s':\n", len(matches), pattern)
+		for _, wt := range matches {
+			message.WriteString("    " + wt.Path + "\n")
 		}
+		message.WriteString("\nRemove them?")
 		p := prompter.New(os.Stdin, os.Stdout, os.Stderr)
-		idx, err := p.Select("Multiple worktrees match '"+worktreeName+"'. Select one:", "", options)
+		confirm, err := p.Confirm(message.String(), false)
 		if err != nil {
 			return fmt.Errorf("prompt failed: %w", err)
 		}
-		targetWorktree = matches[idx]
+		if !confirm {
+			Log.Warnf("Cancelled - no changes made\n")
+			return nil
+		}
+	}
+	return removeWorktrees(matches)
+}
+
+// removeWorktrees removes each of worktrees, continuing past failures, and reports how many
+// were removed and skipped.
+func removeWorktrees(worktrees []git.WorktreeInfo) error {
+	removed, skipped, failed := 0, 0, 0
+	for _, wt := range worktrees {
+		ok, err := removeWorktree(wt, rmOptions())
+		switch {
+		case err != nil:
+			Log.Errorf("Failed to remove '%s': %v\n", wt.Path, err)
+			failed++
+		case ok:
+			removed++
+		default:
+			skipped++
+		}
+	}
+
+	Log.Outf(logger.Green, "\nRemoved %d worktree(s), skipped %d.\n", removed, skipped)
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d worktree(s)", failed)
+	}
+	return nil
+}
+
+// removeMergedWorktrees removes PR worktrees whose pull request has been merged or closed.
+func removeMergedWorktrees() error {
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		return err
+	}
+
+	removed, skipped, failed := 0, 0, 0
+	for _, wt := range worktrees {
+		number, repo, ok := prNumber(wt.Path)
+		if !ok {
+			continue
+		}
+		num := strconv.Itoa(number)
+
+		// The PR may belong to another repository than the current one, such as upstream of a fork.
+		args := []string{"pr", "view", num, "--json", "state,mergedAt"}
+		if repo != "" {
+			args = append(args, "--repo", repo)
+		}
+		stdout, stderr, err := ghExec(args...)
+		if err != nil {
+			Log.Warnf("Skipping '%s': %v\n", wt.Path, ghError("failed to fetch PR #"+num, err, stderr))
+			skipped++
+			continue
+		}
+
+		var prState struct {
+			State    string `json:"state"`
+			MergedAt string `json:"mergedAt"`
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &prState); err != nil {
+			Log.Warnf("Skipping '%s': failed to parse PR #%s: %v\n", wt.Path, num, err)
+			skipped++
+			continue
+		}
+
+		if prState.State != "MERGED" && prState.State != "CLOSED" {
+			continue
+		}
+
+		if wt.Locked {
+			Log.Warnf("Skipping '%s': worktree is locked.\n", wt.Path)
+			skipped++
+			continue
+		}
+
+		if git.HasUncommittedChanges(wt.Path) {
+			Log.Warnf("Skipping '%s': worktree has uncommitted changes.\n", wt.Path)
+			skipped++
+			continue
+		}
+
+		if !forceFlag {
+			if !stdinIsTerminal() {
+				Log.Warnf("Skipping '%s': PR #%s is %s; use --force to remove it without confirmation.\n", wt.Path, num, strings.ToLower(prState.State))
+				skipped++
+				continue
+			}
+			confirmed, err := confirm(fmt.Sprintf("PR #%s is %s. Remove worktree '%s'?", num, strings.ToLower(prState.State), wt.Path), true)
+			if err != nil {
+				return fmt.Errorf("prompt failed: %w", err)
+			}
+			if !confirmed {
+				skipped++
+				continue
+			}
+		}
+
+		// The PR's commits live on GitHub, so its local branch can be deleted even if unmerged locally.
+		if _, err := removeWorktree(wt, removeOptions{ForceBranch: true, KeepBranch: keepBranchFlag}); err != nil {
+			Log.Errorf("Failed to remove '%s': %v\n", wt.Path, err)
+			failed++
+			continue
+		}
+		removed++
+	}
+
+	Log.Outf(logger.Green, "\nRemoved %d worktree(s), skipped %d.\n", removed, skipped)
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d worktree(s)", failed)
+	}
+	return nil
+}
+
+// confirmUnpushedCommits asks whether to remove a worktree whose branch has commits that were
+// not pushed: commits ahead of its upstream or, without an upstream, commits not on any remote
+// branch or HEAD. It returns true if there are none or the user confirmed.
+func confirmUnpushedCommits(wt git.WorktreeInfo) (bool, error) {
+	if wt.Branch == "" {
+		return true, nil
+	}
+
+	var message string
+	upstream, err := git.Upstream(wt.Path)
+	switch {
+	case errors.Is(err, git.ErrNoUpstream):
+		count, commits, err := git.UnmergedCommits(wt.Branch, 5)
+		if err != nil || count == 0 {
+			return true, nil
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "⚠️  Branch '%s' has no upstream, and %d commit(s) on it exist only locally:\n", wt.Branch, count)
+		for _, commit := range commits {
+			fmt.Fprintf(&b, "    %s\n", commit)
+		}
+		if count > len(commits) {
+			fmt.Fprintf(&b, "    ... and %d more\n", count-len(commits))
+		}
+		b.WriteString("\nRemove worktree '" + wt.Path + "' anyway?")
+		message = b.String()
+	case err != nil:
+		return true, nil
+	default:
+		ahead, _, err := git.AheadBehind(wt.Path)
+		if err != nil || ahead == 0 {
+			return true, nil
+		}
+		message = fmt.Sprintf("Branch '%s' is %d commit(s) ahead of '%s' and not pushed. Remove worktree '%s' anyway?", wt.Branch, ahead, upstream, wt.Path)
+	}
+
+	if !stdinIsTerminal() {
+		return false, fmt.Errorf("branch '%s' of worktree '%s' has unpushed commits; use --force to remove it anyway", wt.Branch, wt.Path)
+	}
+	confirmed, err := confirm(message, false)
+	if err != nil {
+		return false, fmt.Errorf("prompt failed: %w", err)
+	}
+	if !confirmed {
+		Log.Warnf("Skipped '%s' - no changes made\n", wt.Path)
+	}
+	return confirmed, nil
+}
+
+// prNumber returns the number of the PR a worktree was created for. It uses the worktree's
+// metadata when present, which also records the owner/repo of the PR, and falls back to the
+// default pr_<number> directory name.
+func prNumber(worktreePath string) (number int, repo string, ok bool) {
+	if md, err := worktree.ReadMetadata(worktreePath); err == nil && md != nil {
+		if md.Owner != "" && md.Repo != "" {
+			repo = md.Owner + "/" + md.Repo
+		}
+		return md.Number, repo, md.Type == worktree.PR
+	}
+	m := regexp.MustCompile(`^pr_(\d+)$`).FindStringSubmatch(filepath.Base(worktreePath))
+	if m == nil {
+		return 0, "", false
+	}
+	n, err := strconv.Atoi(m[1])
+	return n, "", err == nil
+}
+
+// removeOptions controls what removeWorktree does with the worktree's branch.
+type removeOptions struct {
+	// ForceBranch deletes the branch even if it has unmerged commits.
+	ForceBranch bool
+	// KeepBranch keeps the branch, so there are no unpushed commits to ask about.
+	KeepBranch bool
+	// SkipUnpushedCheck does not ask about unpushed commits, for callers that never force-delete
+	// the branch, so that unmerged commits are kept on it.
+	SkipUnpushedCheck bool
+	// BranchHint is added to the error when the branch could not be deleted.
+	BranchHint string
+}
+
+// rmOptions returns the removeOptions set by rm's flags.
+func rmOptions() removeOptions {
+	opts := removeOptions{ForceBranch: forceFlag, KeepBranch: keepBranchFlag}
+	if !forceFlag {
+		opts.BranchHint = "Use --force to delete unmerged branches"
+	}
+	return opts
+}
+
+// removeWorktree removes a worktree and deletes its branch as opts say.
+// It prompts if the worktree has uncommitted changes and reports whether it was removed.
+func removeWorktree(targetWorktree git.WorktreeInfo, opts removeOptions) (bool, error) {
+	// git refuses to remove locked worktrees; explain how to unlock instead of surfacing its exit status.
+	if targetWorktree.Locked {
+		return false, lockedError(targetWorktree)
 	}
 
 	// Handle uncommitted changes prompt.
 	force := forceFlag
 	if !force && git.HasUncommittedChanges(targetWorktree.Path) {
-		p := prompter.New(os.Stdin, os.Stdout, os.Stderr)
-		confirm, err := p.Confirm("Worktree has uncommitted changes. Remove anyway?", false)
+		if !stdinIsTerminal() {
+			return false, fmt.Errorf("worktree '%s' has uncommitted changes; use --force to remove it anyway", targetWorktree.Path)
+		}
+
+		message := fmt.Sprintf("Worktree '%s' has uncommitted changes. Remove anyway?", targetWorktree.Path)
+		if summary, err := git.StatusSummary(targetWorktree.Path, 10); err == nil {
+			message = fmt.Sprintf("Worktree '%s' has uncommitted changes:\n%s\n\nRemove anyway?", targetWorktree.Path, summary)
+		}
+
+		confirmed, err := confirm(message, false)
 		if err != nil {
-			return fmt.Errorf("prompt failed: %w", err)
+			return false, fmt.Errorf("prompt failed: %w", err)
 		}
-		if !confirm {
-			Log.Warnf("Cancelled - no changes made\n")
-			return nil
+		if !confirmed {
+			Log.Warnf("Skipped '%s' - no changes made\n", targetWorktree.Path)
+			return false, nil
 		}
 		force = true // User confirmed.
 	}
 
-	// 1. Remove the worktree directory and git metadata.
+	// A clean worktree can still hold commits that exist nowhere else, unless its branch is kept.
+	if !forceFlag && !opts.KeepBranch && !opts.SkipUnpushedCheck {
+		if ok, err := confirmUnpushedCommits(targetWorktree); err != nil || !ok {
+			return false, err
+		}
+	}
+
 	Log.Infof("Removing worktree '%s'...\n", targetWorktree.Path)
-	if err := worktree.Remove(targetWorktree.Path, force); err != nil {
-		return fmt.Errorf("failed to remove worktree: %w", err)
+	ctx, cancel := gitContext()
+	defer cancel()
+	result, err := ghwt.Remove(ctx, targetWorktree, ghwt.RemoveOptions{Force: force, KeepBranch: opts.KeepBranch, ForceBranch: opts.ForceBranch})
+	if result == nil {
+		return false, err
 	}
 	Log.Outf(logger.Green, "Successfully removed worktree directory.\n")
 
-	// 2. Delete the associated branch if we found one.
-	if targetWorktree.Branch != "" {
-		Log.Infof("Deleting branch '%s'...\n", targetWorktree.Branch)
-		if err := git.BranchDelete(targetWorktree.Branch, true); err != nil {
-			// This is not a fatal error, as the primary goal (removing the worktree) succeeded.
-			// The branch might be the main branch or have other worktrees, so git will prevent its deletion.
-			return fmt.Errorf("worktree removed, but failed to delete branch '%s': %w. You may need to remove it manually", targetWorktree.Branch, err)
+	switch {
+	case err != nil:
+		// This is not a fatal error, as the primary goal (removing the worktree) succeeded.
+		// The branch might be unmerged or have other worktrees, so git will prevent its deletion.
+		if opts.BranchHint != "" {
+			return true, fmt.Errorf("%w. %s", err, opts.BranchHint)
 		}
-		Log.Outf(logger.Green, "Successfully deleted branch '%s'.\n", targetWorktree.Branch)
+		return true, err
+	case result.BranchInMainWorktree:
+		Log.Warnf("Keeping branch '%s' since it is checked out in the main worktree.\n", result.Branch)
+		return true, nil
+	case result.BranchDeleted:
+		Log.Outf(logger.Green, "Successfully deleted branch '%s'.\n", result.Branch)
+	case result.Branch != "":
+		Log.Outf(logger.Green, "\nWorktree '%s' removed successfully; kept branch '%s'.\n", targetWorktree.Path, result.Branch)
+		return true, nil
 	}
 
 	Log.Outf(logger.Green, "\nWorktree '%s' and branch '%s' removed successfully.\n", targetWorktree.Path, targetWorktree.Branch)
-	return nil
+	return true, nil
 }
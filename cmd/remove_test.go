@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/worktree"
+	"github.com/ffalor/gh-wt/pkg/ghwt"
+)
+
+func TestRemoveDirtyWorktree(t *testing.T) {
+	repo, base := setupRepo(t)
+	path := filepath.Join(base, "r", "dirty")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "dirty", path)
+	writeTestFile(t, filepath.Join(path, "notes.txt"), "work in progress")
+	wt := worktreeAt(t, path)
+
+	// Without a terminal nothing can be confirmed, so nothing is removed.
+	stubPrompts(t, false, true)
+	if _, err := removeWorktree(wt, rmOptions()); err == nil {
+		t.Fatal("removeWorktree() without a terminal succeeded, want an error")
+	}
+	if !exists(filepath.Join(path, "notes.txt")) {
+		t.Fatal("the dirty worktree was removed without confirmation")
+	}
+
+	// Declining the prompt keeps the worktree, its changes, and its branch.
+	asked := stubPrompts(t, true, false)
+	removed, err := removeWorktree(wt, rmOptions())
+	if err != nil || removed {
+		t.Fatalf("removeWorktree() = %v, %v; want false, nil", removed, err)
+	}
+	if len(*asked) != 1 {
+		t.Errorf("asked %d questions, want 1: %q", len(*asked), *asked)
+	}
+	if !exists(filepath.Join(path, "notes.txt")) || !git.BranchExists("dirty") {
+		t.Fatal("the dirty worktree or its branch was removed after declining")
+	}
+
+	// --force removes it without asking.
+	asked = stubPrompts(t, false, false)
+	setFlag(t, &forceFlag, true)
+	removed, err = removeWorktree(wt, rmOptions())
+	if err != nil || !removed {
+		t.Fatalf("removeWorktree() with --force = %v, %v; want true, nil", removed, err)
+	}
+	if len(*asked) != 0 {
+		t.Errorf("--force asked %q", *asked)
+	}
+	if exists(path) || git.WorktreeIsRegistered(path) {
+		t.Error("the worktree still exists after removing it with --force")
+	}
+	if git.BranchExists("dirty") {
+		t.Error("the merged branch of the worktree was not deleted")
+	}
+}
+
+func TestRemoveMergedWorktreesUsesRecordedRepository(t *testing.T) {
+	repo, base := setupRepo(t)
+	log := fakeGh(t, `echo '{"state":"MERGED","mergedAt":"2026-01-01T00:00:00Z"}'`)
+	setFlag(t, &forceFlag, true)
+
+	path := filepath.Join(base, "r", "pr_5")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "pr_5", path)
+	if err := worktree.WriteMetadata(path, &worktree.WorktreeInfo{
+		Type: worktree.PR, Owner: "upstream", Repo: "r", Number: 5, BranchName: "pr_5",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := removeMergedWorktrees(); err != nil {
+		t.Fatalf("removeMergedWorktrees() error = %v", err)
+	}
+	want := []string{"pr view 5 --json state,mergedAt --repo upstream/r"}
+	if got := ghCalls(t, log); !slices.Equal(got, want) {
+		t.Errorf("gh calls = %q, want %q", got, want)
+	}
+	if exists(path) {
+		t.Error("the worktree of the merged PR was not removed")
+	}
+}
+
+func TestRemoveMergedWorktreesConfirmation(t *testing.T) {
+	tests := []struct {
+		name     string
+		terminal bool
+		answer   bool
+		asked    int
+		removed  bool
+	}{
+		{"no terminal", false, true, 0, false},
+		{"confirmed", true, true, 1, true},
+		{"declined", true, false, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, base := setupRepo(t)
+			fakeGh(t, `echo '{"state":"MERGED","mergedAt":"2026-01-01T00:00:00Z"}'`)
+			asked := stubPrompts(t, tt.terminal, tt.answer)
+
+			path := filepath.Join(base, "r", "pr_5")
+			runGit(t, repo, "worktree", "add", "-q", "-b", "pr_5", path)
+			if err := worktree.WriteMetadata(path, &worktree.WorktreeInfo{
+				Type: worktree.PR, Owner: "o", Repo: "r", Number: 5, BranchName: "pr_5",
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := removeMergedWorktrees(); err != nil {
+				t.Fatalf("removeMergedWorktrees() error = %v", err)
+			}
+			if len(*asked) != tt.asked {
+				t.Errorf("asked %q, want %d question(s)", *asked, tt.asked)
+			}
+			if removed := !exists(path); removed != tt.removed {
+				t.Errorf("removed = %v, want %v", removed, tt.removed)
+			}
+		})
+	}
+}
+
+func TestRemoveKeepBranch(t *testing.T) {
+	repo, base := setupRepo(t)
+	path := filepath.Join(base, "r", "local-work")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "local-work", path)
+	runGit(t, path, "commit", "-q", "--allow-empty", "-m", "only here")
+
+	// The unpushed commit stays on the kept branch, so there is nothing to confirm.
+	asked := stubPrompts(t, false, false)
+	setFlag(t, &keepBranchFlag, true)
+	removed, err := removeWorktree(worktreeAt(t, path), rmOptions())
+	if err != nil || !removed {
+		t.Fatalf("removeWorktree() = %v, %v; want true, nil", removed, err)
+	}
+	if len(*asked) != 0 {
+		t.Errorf("asked %q", *asked)
+	}
+	if exists(path) {
+		t.Error("the worktree was not removed")
+	}
+	if !git.BranchExists("local-work") {
+		t.Error("the branch was deleted despite --keep-branch")
+	}
+}
+
+func TestRemoveUnmergedBranch(t *testing.T) {
+	repo, base := setupRepo(t)
+	path := filepath.Join(base, "r", "local-work")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "local-work", path)
+	runGit(t, path, "commit", "-q", "--allow-empty", "-m", "only here")
+
+	// Confirming the removal of unpushed work removes the worktree, but the unmerged branch
+	// needs --force to be deleted.
+	asked := stubPrompts(t, true, true)
+	removed, err := removeWorktree(worktreeAt(t, path), rmOptions())
+	if !removed || !errors.Is(err, ghwt.ErrBranchNotDeleted) {
+		t.Fatalf("removeWorktree() = %v, %v; want true, %v", removed, err, ghwt.ErrBranchNotDeleted)
+	}
+	if len(*asked) != 1 {
+		t.Errorf("asked %d questions, want 1: %q", len(*asked), *asked)
+	}
+	if !git.BranchExists("local-work") {
+		t.Error("the unmerged branch was deleted without --force")
+	}
+}
+
+func TestRemoveLockedWorktree(t *testing.T) {
+	repo, base := setupRepo(t)
+	path := filepath.Join(base, "r", "locked")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "locked", path)
+	runGit(t, repo, "worktree", "lock", "--reason", "on a USB drive", path)
+	wt := worktreeAt(t, path)
+	if !wt.Locked || wt.LockReason != "on a USB drive" {
+		t.Fatalf("worktree = %+v, want it locked with its reason", wt)
+	}
+
+	stubPrompts(t, true, true)
+	for _, force := range []bool{false, true} {
+		setFlag(t, &forceFlag, force)
+		removed, err := removeWorktree(wt, rmOptions())
+		if removed || err == nil {
+			t.Fatalf("removeWorktree() with force %v = %v, %v; want an error", force, removed, err)
+		}
+		for _, want := range []string{"is locked", "on a USB drive", "gh wt unlock locked"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error %q does not contain %q", err, want)
+			}
+		}
+	}
+	if !exists(path) || !git.BranchExists("locked") {
+		t.Error("the locked worktree or its branch was removed")
+	}
+
+	if _, err := ghwt.Remove(context.Background(), wt, ghwt.RemoveOptions{Force: true}); !errors.Is(err, ghwt.ErrLocked) {
+		t.Errorf("ghwt.Remove() error = %v, want %v", err, ghwt.ErrLocked)
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+// renameCmd represents the rename command.
+var renameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a worktree and its branch",
+	Long: `Rename a worktree directory and its branch together.
+
+The worktree stays in the same parent directory. Slashes in the new name are kept
+in the branch name and replaced with '_' in the directory name, like 'gh wt add'.`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeWorktreeNames(cmd, args, toComplete)
+	},
+	RunE: runRename,
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	if !git.IsGitRepository(".") {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	target, err := selectWorktree(args[0])
+	if err != nil || target == nil {
+		return err
+	}
+
+	mainPath, err := git.GetMainWorktreePath()
+	if err != nil {
+		return err
+	}
+	if resolvePath(target.Path) == resolvePath(mainPath) {
+		return fmt.Errorf("cannot rename the main worktree")
+	}
+	if target.Locked {
+		return lockedError(*target)
+	}
+
+	newBranch := SanitizeBranchName(args[1])
+	newDir := SanitizeWorktreeName(newBranch)
+	if newBranch == "" || newDir == "" {
+		return fmt.Errorf("invalid name '%s'", args[1])
+	}
+	newPath := filepath.Join(filepath.Dir(target.Path), newDir)
+
+	// Check both sides before changing anything so a collision cannot leave them out of sync.
+	if newPath != target.Path {
+		if _, err := os.Lstat(newPath); err == nil {
+			return fmt.Errorf("destination '%s' already exists", newPath)
+		}
+	}
+	renameBranch := target.Branch != "" && target.Branch != newBranch
+	if renameBranch && git.BranchExists(newBranch) {
+		return fmt.Errorf("branch '%s' already exists", newBranch)
+	}
+
+	if newPath != target.Path {
+		ctx, cancel := gitContext()
+		defer cancel()
+		Log.Infof("Moving worktree '%s' to '%s'...\n", target.Path, newPath)
+		if err := git.WorktreeMove(ctx, target.Path, newPath); err != nil {
+			return fmt.Errorf("failed to move worktree: %w", err)
+		}
+	}
+
+	if renameBranch {
+		Log.Infof("Renaming branch '%s' to '%s'...\n", target.Branch, newBranch)
+		if err := git.BranchRename(target.Branch, newBranch); err != nil {
+			return fmt.Errorf("worktree moved, but failed to rename branch '%s': %w", target.Branch, err)
+		}
+	}
+
+	Log.Outf(logger.Green, "\nWorktree renamed successfully!\n")
+	Log.Outf(logger.Default, "Location: %s\n", newPath)
+	if target.Branch != "" {
+		Log.Outf(logger.Default, "Branch: %s\n", newBranch)
+	}
+	return nil
+}
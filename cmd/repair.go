@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+// repairCmd represents the repair command.
+var repairCmd = &cobra.Command{
+	Use:   "repair [path]...",
+	Short: "Repair worktree links after moving worktrees or the repository",
+	Long: `Repair the links between worktrees and the repository with 'git worktree repair'.
+
+Run it from the repository after moving it, or after moving worktrees by hand.
+Worktrees git still knows about are repaired automatically. Pass the new paths of
+moved worktrees, or use --scan to look for worktrees of this repository with broken
+links in the worktree directory.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runRepair,
+}
+
+var repairScanFlag bool
+
+func init() {
+	repairCmd.Flags().BoolVar(&repairScanFlag, "scan", false, "find worktrees of this repository with broken links in the worktree directory")
+	rootCmd.AddCommand(repairCmd)
+}
+
+func runRepair(cmd *cobra.Command, args []string) error {
+	if !git.IsGitRepository(".") {
+		return fmt.Errorf("not in a git repository")
+	}
+
+	paths := args
+	if repairScanFlag {
+		found, err := brokenWorktreeDirs()
+		if err != nil {
+			return err
+		}
+		for _, dir := range found {
+			Log.Infof("Found worktree with broken links: %s\n", dir)
+		}
+		paths = append(paths, found...)
+	}
+
+	if err := git.WorktreeRepair(paths...); err != nil {
+		return fmt.Errorf("failed to repair worktrees: %w", err)
+	}
+	Log.Outf(logger.Green, "Worktree links repaired.\n")
+	return nil
+}
+
+// brokenWorktreeDirs returns directories in the worktree directory whose .git file points at
+// this repository but that git does not know about, or whose .git file points at a missing
+// directory, as is the case after moving the repository.
+func brokenWorktreeDirs() ([]string, error) {
+	commonDir, err := git.GetGitCommonDir(".")
+	if err != nil {
+		return nil, err
+	}
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		return nil, err
+	}
+	registered := make(map[string]bool, len(worktrees))
+	for _, wt := range worktrees {
+		registered[resolvePath(wt.Path)] = true
+	}
+
+	dirs, err := repoWorktreeDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	var broken []string
+	for _, dir := range dirs {
+		gitDir, ok := readGitFile(dir)
+		if !ok {
+			continue
+		}
+		if _, err := os.Stat(gitDir); err != nil {
+			broken = append(broken, dir)
+			continue
+		}
+		// A live link into another repository is not ours to repair.
+		if linksInto(gitDir, commonDir) && !registered[resolvePath(dir)] {
+			broken = append(broken, dir)
+		}
+	}
+	return broken, nil
+}
+
+// linksInto reports whether gitDir, read from a worktree's .git file, is inside commonDir.
+func linksInto(gitDir, commonDir string) bool {
+	return strings.HasPrefix(resolvePath(gitDir), resolvePath(commonDir)+string(filepath.Separator))
+}
+
+// ownedBy reports whether dir may be a leftover worktree of the repository whose common git
+// directory is commonDir. A directory with its own .git directory is a clone, and one whose .git
+// file points outside commonDir, whether or not the link still works, is another repository's.
+func ownedBy(dir, commonDir string) bool {
+	if fi, err := os.Stat(filepath.Join(dir, ".git")); err == nil && fi.IsDir() {
+		return false
+	}
+	if gitDir, ok := readGitFile(dir); ok {
+		return linksInto(gitDir, commonDir)
+	}
+	return true
+}
+
+// readGitFile returns the git directory a linked worktree's .git file points to.
+func readGitFile(dir string) (string, bool) {
+	data, err := os.ReadFile(filepath.Join(dir, ".git"))
+	if err != nil {
+		return "", false
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return "", false
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	return gitDir, true
+}
@@ -1,13 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"runtime"
 	"runtime/debug"
 	"strings"
 
 	"github.com/ffalor/gh-wt/internal/config"
+	"github.com/ffalor/gh-wt/internal/git"
 	"github.com/ffalor/gh-wt/internal/logger"
 	"github.com/spf13/cobra"
 )
@@ -16,8 +20,13 @@ var (
 	// Used for flags.
 	forceFlag bool
 	verbose   bool
+	quiet     bool
 	noColor   bool
 	cliArgs   string
+	cliArgv   []string
+
+	logLevelFlag     string
+	worktreeBaseFlag string
 )
 
 // Version is the current version of the CLI.
@@ -52,7 +61,7 @@ func buildVersion(version, commit, date, builtBy string) string {
 }
 
 // Log is the package-level logger instance.
-var Log = logger.NewLogger(false, true)
+var Log = logger.NewLogger(logger.LevelInfo, true)
 
 // rootCmd represents the base command when called without any subcommands.
 var rootCmd = &cobra.Command{
@@ -76,15 +85,95 @@ Examples:
   # Remove a worktree
   gh wt rm pr_123`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		_, err := config.Load()
+		// cobra checks flag groups only after this hook; check them here so they are usage errors.
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+		level, err := logLevel()
+		if err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+		Log = logger.NewLogger(level, !noColor)
+		git.TraceOutput = Log.DebugWriter()
+		git.Quiet = Log.Quiet()
+
+		_, err = config.Load()
 		if err != nil {
 			return err
 		}
-		Log = logger.NewLogger(verbose, !noColor)
+
+		// The git binary must be known before the first git command runs.
+		if err := useGitBinary(); err != nil {
+			return err
+		}
+		if git.IsGitRepository(".") {
+			if root, err := git.GetMainWorktreeDir(); err == nil {
+				ignored, err := config.LoadRepo(root)
+				if err != nil {
+					return err
+				}
+				if len(ignored) > 0 {
+					Log.Warnf("⚠️  Ignoring %s in %s: repository config files may only set %s\n",
+						strings.Join(ignored, ", "), config.RepoConfigFileUsed(), strings.Join(config.RepoKeys, ", "))
+				}
+			}
+		}
+		// --worktree-base overrides worktree_dir, even from a repository config, for this run only.
+		if worktreeBaseFlag != "" {
+			base, err := config.ExpandPath(worktreeBaseFlag)
+			if err == nil {
+				base, err = filepath.Abs(base)
+			}
+			if err != nil {
+				return fmt.Errorf("invalid --worktree-base '%s': %w", worktreeBaseFlag, err)
+			}
+			config.Set("worktree_dir", base)
+		}
 		return nil
 	},
 }
 
+// logLevel returns the level set with --log-level, or the one implied by --verbose and --quiet.
+func logLevel() (logger.Level, error) {
+	switch {
+	case logLevelFlag != "":
+		return logger.ParseLevel(logLevelFlag)
+	case verbose:
+		return logger.LevelDebug, nil
+	case quiet:
+		return logger.LevelWarn, nil
+	default:
+		return logger.LevelInfo, nil
+	}
+}
+
+// useGitBinary points the git package at the configured git_binary, if any.
+func useGitBinary() error {
+	cfg, err := config.Get()
+	if err != nil || cfg.GitBinary == "" {
+		return nil
+	}
+	path, err := exec.LookPath(cfg.GitBinary)
+	if err != nil {
+		return fmt.Errorf("git_binary '%s' is not an executable: %w", cfg.GitBinary, err)
+	}
+	git.Binary = path
+	return nil
+}
+
+// gitContext returns a context bounded by the configured git timeout.
+// A timeout of zero or less disables the limit.
+func gitContext() (context.Context, context.CancelFunc) {
+	timeout := config.DefaultGitTimeout
+	if cfg, err := config.Get(); err == nil {
+		timeout = cfg.GitTimeout
+	}
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
 	// Find and store arguments after --
@@ -97,10 +186,12 @@ func Execute() {
 	}
 
 	if dashDashIndex != -1 {
-		cliArgs = strings.Join(os.Args[dashDashIndex+1:], " ")
+		cliArgv = os.Args[dashDashIndex+1:]
+		cliArgs = strings.Join(cliArgv, " ")
 		os.Args = os.Args[:dashDashIndex]
 	}
 
+	markUsageErrors(rootCmd)
 	err := rootCmd.Execute()
 	if err != nil {
 		if Log != nil {
@@ -108,15 +199,20 @@ func Execute() {
 		} else {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		}
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().BoolVarP(&forceFlag, "force", "f", false, "force operation without prompts")
-	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "verbose output, including every git and gh command run")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "debug", false, "alias for --verbose")
+	_ = rootCmd.PersistentFlags().MarkHidden("debug")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only print results such as the worktree path")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable color output")
+	rootCmd.PersistentFlags().StringVar(&worktreeBaseFlag, "worktree-base", "", "directory to create worktrees in for this run, overriding worktree_dir")
+	rootCmd.PersistentFlags().StringVar(&logLevelFlag, "log-level", "", "minimum level of log messages on stderr: debug, info, warn, or error (overrides --verbose and --quiet)")
 
 	// Version flag
 	rootCmd.Version = buildVersion(Version, Commit, Date, BuiltBy)
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"github.com/ffalor/gh-wt/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+// shellInitCmd represents the shell-init command.
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init [bash|zsh|fish|powershell]",
+	Short: "Print a shell function that changes into new and switched-to worktrees",
+	Long: `Print a shell function named wt that runs gh wt and, after add, create, and switch,
+changes the shell into the worktree. Without an argument, the shell is taken from $SHELL.
+
+Add it to your shell's startup file:
+
+  bash  (~/.bashrc):   eval "$(gh wt shell-init bash)"
+  zsh   (~/.zshrc):    eval "$(gh wt shell-init zsh)"
+  fish  (config.fish): gh wt shell-init fish | source
+  PowerShell ($PROFILE): Invoke-Expression (gh wt shell-init powershell | Out-String)
+
+Then wt add my-feature creates the worktree and changes into it. Other commands, such as
+wt rm, are passed to gh wt unchanged.`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{string(shell.Bash), string(shell.Zsh), string(shell.Fish), string(shell.PowerShell)},
+	RunE:      runShellInit,
+}
+
+func init() {
+	rootCmd.AddCommand(shellInitCmd)
+}
+
+func runShellInit(cmd *cobra.Command, args []string) error {
+	sh := shell.Detect()
+	if len(args) == 1 {
+		var err error
+		if sh, err = shell.Parse(args[0]); err != nil {
+			return err
+		}
+	}
+
+	switch sh {
+	case shell.Fish:
+		Log.Plainf(fishInit)
+	case shell.PowerShell:
+		Log.Plainf(powerShellInit)
+	default:
+		Log.Plainf(posixInit)
+	}
+	return nil
+}
+
+// The wrappers capture the path printed by gh wt add --print-path --quiet or gh wt switch,
+// which print nothing else on stdout. Help output is passed through instead of being captured.
+// The flags go right after the subcommand, since everything after -- is passed to the action.
+
+const posixInit = `wt() {
+  case "$1" in
+    add|create|switch) ;;
+    *) command gh wt "$@"; return ;;
+  esac
+  case " $* " in
+    *" -h "*|*" --help "*) command gh wt "$@"; return ;;
+  esac
+  local sub=$1 dir
+  shift
+  if [ "$sub" = switch ]; then
+    dir=$(command gh wt switch "$@") || return
+  else
+    dir=$(command gh wt "$sub" --print-path --quiet "$@") || return
+  fi
+  dir=${dir##*$'\n'}
+  [ -n "$dir" ] && cd -- "$dir"
+}
+`
+
+const fishInit = `function wt --description 'gh wt, changing into the worktree after add and switch'
+    if not contains -- "$argv[1]" add create switch; or contains -- -h $argv; or contains -- --help $argv
+        command gh wt $argv
+        return
+    end
+    set -l dir
+    if test "$argv[1]" = switch
+        set dir (command gh wt $argv); or return
+    else
+        set dir (command gh wt $argv[1] --print-path --quiet $argv[2..-1]); or return
+    end
+    if set -q dir[1]
+        cd $dir[-1]
+    end
+end
+`
+
+const powerShellInit = `function wt {
+    if ($args.Count -eq 0 -or $args[0] -notin 'add', 'create', 'switch' -or $args -contains '-h' -or $args -contains '--help') {
+        gh wt @args
+        return
+    }
+    if ($args[0] -eq 'switch') {
+        $dir = gh wt @args
+    } else {
+        $rest = @($args | Select-Object -Skip 1)
+        $dir = gh wt $args[0] --print-path --quiet @rest
+    }
+    if ($LASTEXITCODE -eq 0 -and $dir) {
+        Set-Location -LiteralPath @($dir)[-1]
+    }
+}
+`
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPosixInitActionArguments(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil || runtime.GOOS == "windows" {
+		t.Skip("bash is not available")
+	}
+	dir := t.TempDir()
+	worktree := filepath.Join(dir, "wt")
+	if err := os.Mkdir(worktree, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// A fake gh that records its arguments and prints the worktree path, like gh wt add --print-path.
+	bin := filepath.Join(dir, "bin")
+	log := filepath.Join(dir, "gh.log")
+	writeTestFile(t, filepath.Join(bin, "gh"), "#!/bin/sh\nprintf '%s\\n' \"$@\" > '"+log+"'\necho '"+worktree+"'\n")
+	if err := os.Chmod(filepath.Join(bin, "gh"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	script := posixInit + `wt add https://github.com/o/r/pull/1 --action claude -- "/review this"` + "\npwd\n"
+	cmd := exec.Command(bash, "-c", script)
+	cmd.Env = append(os.Environ(), "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("bash: %v\n%s", err, out)
+	}
+	if got := strings.TrimSpace(string(out)); got != worktree {
+		t.Errorf("wt changed into %q, want %q", got, worktree)
+	}
+
+	args, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"wt", "add", "--print-path", "--quiet", "https://github.com/o/r/pull/1", "--action", "claude", "--", "/review this"}
+	if got := strings.Split(strings.TrimSuffix(string(args), "\n"), "\n"); !slices.Equal(got, want) {
+		t.Errorf("gh was run with %q, want %q", got, want)
+	}
+}
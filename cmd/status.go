@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// statusCmd represents the status command.
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show a summary of all worktrees",
+	Long: `Show a summary of all worktrees of the current repository.
+
+For each worktree the branch, whether it has uncommitted changes, the number of
+commits ahead of and behind its upstream, and the last commit subject are shown.
+Worktrees without an upstream show '-' for ahead/behind.`,
+	Args: cobra.NoArgs,
+	RunE: runStatus,
+}
+
+var statusJobsFlag int
+
+func init() {
+	statusCmd.Flags().IntVarP(&statusJobsFlag, "jobs", "j", runtime.NumCPU(), "number of worktrees to inspect in parallel")
+	rootCmd.AddCommand(statusCmd)
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	if !git.IsGitRepository(".") {
+		return fmt.Errorf("not in a git repository")
+	}
+	if statusJobsFlag < 1 {
+		return fmt.Errorf("--jobs must be at least 1, got %d", statusJobsFlag)
+	}
+
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		return err
+	}
+
+	// Each worktree needs several git calls, so collect them concurrently.
+	// Rows are stored by index to keep git's worktree order regardless of completion order.
+	rows := make([]string, len(worktrees))
+	sem := make(chan struct{}, statusJobsFlag)
+	var wg sync.WaitGroup
+	for i, wt := range worktrees {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			rows[i] = statusRow(wt)
+		}()
+	}
+	wg.Wait()
+
+	w := tabwriter.NewWriter(Log.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tBRANCH\tSTATE\tAHEAD/BEHIND\tLAST COMMIT")
+	for _, row := range rows {
+		fmt.Fprintln(w, row)
+	}
+	return w.Flush()
+}
+
+// statusRow returns the tab-separated status line of a worktree.
+func statusRow(wt git.WorktreeInfo) string {
+	branch := wt.Branch
+	if wt.Detached {
+		branch = "(detached)"
+	}
+	if wt.Bare {
+		return fmt.Sprintf("%s\t(bare)\t-\t-\t-", wt.Path)
+	}
+
+	state := "clean"
+	if git.HasUncommittedChanges(wt.Path) {
+		state = "dirty"
+	}
+
+	aheadBehind := "-"
+	ahead, behind, err := git.AheadBehind(wt.Path)
+	switch {
+	case err == nil:
+		aheadBehind = fmt.Sprintf("+%d/-%d", ahead, behind)
+	case !errors.Is(err, git.ErrNoUpstream):
+		Log.Debugf("Failed to compare %s with its upstream: %v\n", wt.Path, err)
+	}
+
+	subject, err := git.LastCommitSubject(wt.Path)
+	if err != nil {
+		subject = "-"
+	}
+
+	return fmt.Sprintf("%s\t%s\t%s\t%s\t%s", wt.Path, branch, state, aheadBehind, subject)
+}
@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/prompter"
+	"github.com/ffalor/gh-wt/internal/config"
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/logger"
+	"github.com/ffalor/gh-wt/internal/worktree"
+	"github.com/ffalor/gh-wt/pkg/ghwt"
+	"github.com/spf13/cobra"
+)
+
+// switchCmd represents the switch command.
+var switchCmd = &cobra.Command{
+	Use:   "switch [name|number|url]",
+	Short: "Print the path of a worktree",
+	Long: `Print the absolute path of a worktree so the shell can change into it.
+
+A worktree can be referenced by its name, a PR or issue number, or a PR or issue URL.
+Only the path is written to stdout, so the output can be used with command substitution.
+Without an argument, pick one of the recently used worktrees (see gh wt recent).
+
+Examples:
+  cd "$(gh wt switch pr_123)"
+  cd "$(gh wt switch 123)"
+  cd "$(gh wt switch https://github.com/owner/repo/pull/123)"`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeWorktreeNames,
+	RunE:              runSwitch,
+}
+
+func init() {
+	rootCmd.AddCommand(switchCmd)
+}
+
+func runSwitch(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		path, err := pickRecent()
+		if err != nil {
+			return err
+		}
+		recordRecent(path)
+		Log.Plainf("%s\n", path)
+		return nil
+	}
+	input := args[0]
+
+	matches, err := matchWorktreePaths(input)
+	if err != nil {
+		return err
+	}
+
+	if len(matches) == 0 {
+		return withExitCode(ExitNotFound, fmt.Errorf("worktree '%s' not found", input))
+	}
+
+	path := matches[0]
+	if len(matches) > 1 {
+		// Prompt on stderr so command substitution only captures the path.
+		p := prompter.New(os.Stdin, os.Stderr, os.Stderr)
+		idx, err := p.Select("Multiple worktrees match '"+input+"'. Select one:", "", matches)
+		if err != nil {
+			return fmt.Errorf("prompt failed: %w", err)
+		}
+		path = matches[idx]
+	}
+
+	recordRecent(path)
+	Log.Plainf("%s\n", path)
+	return nil
+}
+
+// worktreeCandidates returns the worktrees that the input could refer to.
+// PR and issue URLs map to their generated names, while bare numbers may refer to either.
+func worktreeCandidates(input string) ([]*worktree.WorktreeInfo, error) {
+	if n, err := strconv.Atoi(input); err == nil && n > 0 {
+		return []*worktree.WorktreeInfo{
+			{Type: worktree.PR, Number: n, WorktreeName: fmt.Sprintf("pr_%d", n)},
+			{Type: worktree.Issue, Number: n, WorktreeName: fmt.Sprintf("issue_%d", n)},
+		}, nil
+	}
+
+	// owner/repo#123 and #123 may also refer to either a PR or an issue.
+	if owner, repo, n, ok := ghwt.ParseShorthand(input); ok {
+		return []*worktree.WorktreeInfo{
+			{Type: worktree.PR, Owner: owner, Repo: repo, Number: n, WorktreeName: fmt.Sprintf("pr_%d", n)},
+			{Type: worktree.Issue, Owner: owner, Repo: repo, Number: n, WorktreeName: fmt.Sprintf("issue_%d", n)},
+		}, nil
+	}
+
+	worktreeType, err := ghwt.DetermineType(input)
+	if err != nil {
+		return nil, err
+	}
+
+	switch worktreeType {
+	case worktree.PR, worktree.Issue:
+		ref, _ := ghwt.ParseURL(input)
+		return []*worktree.WorktreeInfo{{
+			Type:         ref.Type,
+			Owner:        ref.Owner,
+			Repo:         ref.Repo,
+			Number:       ref.Number,
+			WorktreeName: fmt.Sprintf("%s_%d", ref.Type, ref.Number),
+		}}, nil
+	case ghwt.Commit:
+		// Worktrees for a commit are named sha_<short SHA>, like the branch created for them.
+		_, sha, _ := ghwt.ParseCommitURL(input)
+		if short, err := git.ShortSHA(sha); err == nil {
+			sha = short
+		}
+		return []*worktree.WorktreeInfo{{Type: worktree.Local, WorktreeName: "sha_" + sha}}, nil
+	default:
+		candidates := []*worktree.WorktreeInfo{{Type: worktree.Local, WorktreeName: input}}
+		if name := SanitizeWorktreeName(input); name != input {
+			candidates = append(candidates, &worktree.WorktreeInfo{Type: worktree.Local, WorktreeName: name})
+		}
+		return candidates, nil
+	}
+}
+
+// findWorktreePaths returns the absolute paths of worktrees under the worktree base
+// matching the input. Worktrees of the current repository are preferred when present.
+func findWorktreePaths(input string) ([]string, error) {
+	cfg, err := config.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := worktreeCandidates(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recorded metadata finds PR and issue worktrees whatever their name; names are the fallback.
+	if matches := metadataMatches(candidates); len(matches) > 0 {
+		return matches, nil
+	}
+
+	// Look in the current repository first, then across all repositories.
+	type searchDir struct{ repo, baseDir, pathTemplate string }
+	searches := []searchDir{{worktree.Wildcard, cfg.WorktreeBase, cfg.WorktreePathTemplate}}
+	if git.IsGitRepository(".") {
+		if repoName, err := git.GetRepoName(); err == nil {
+			baseDir, pathTemplate := worktreeLayout(cfg)
+			searches = append([]searchDir{{repoName, baseDir, pathTemplate}}, searches...)
+		}
+	}
+
+	for _, search := range searches {
+		var matches []string
+		for _, candidate := range candidates {
+			// Unknown placeholders are turned into wildcards for globbing.
+			info := *candidate
+			if info.Owner == "" {
+				info.Owner = worktree.Wildcard
+			}
+			if info.Repo == "" {
+				info.Repo = search.repo
+			}
+			info.BranchName = worktree.Wildcard
+
+			if cfg.WorktreeNameTemplate != "" && info.Type != worktree.Local {
+				info.WorktreeName, err = worktree.RenderName(cfg.WorktreeNameTemplate, &info)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			path, err := worktree.RenderPath(search.baseDir, search.pathTemplate, &info)
+			if err != nil {
+				return nil, err
+			}
+
+			paths, err := filepath.Glob(worktree.GlobPattern(path))
+			if err != nil {
+				return nil, fmt.Errorf("failed to search worktree directory: %w", err)
+			}
+			for _, path := range paths {
+				if abs, err := filepath.Abs(path); err == nil {
+					path = abs
+				}
+				if !slices.Contains(matches, path) {
+					matches = append(matches, path)
+				}
+			}
+		}
+
+		if len(matches) > 0 {
+			return matches, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// matchWorktreePaths is findWorktreePaths with a fallback to fuzzy matching worktree names
+// when nothing matches exactly.
+func matchWorktreePaths(input string) ([]string, error) {
+	matches, err := findWorktreePaths(input)
+	if err != nil || len(matches) > 0 {
+		return matches, err
+	}
+	return fuzzyWorktreePaths(input)
+}
+
+// fuzzyWorktreePaths returns the paths of the worktrees under the worktree base whose names best
+// match input (see worktree.MatchScore). Worktrees of the current repository are preferred when
+// any of them match. Numbers, URLs and references never match fuzzily.
+func fuzzyWorktreePaths(input string) ([]string, error) {
+	if !isFuzzyQuery(input) {
+		return nil, nil
+	}
+	cfg, err := config.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	var searches [][]string
+	if git.IsGitRepository(".") {
+		if dirs, err := repoWorktreeDirs(); err == nil {
+			searches = append(searches, dirs)
+		}
+	}
+	dirs, err := worktreeDirs(cfg.WorktreeBase, cfg.WorktreePathTemplate, worktree.Wildcard)
+	if err != nil {
+		return nil, err
+	}
+	searches = append(searches, dirs)
+
+	for _, dirs := range searches {
+		names := make([]string, len(dirs))
+		for i, dir := range dirs {
+			names[i] = filepath.Base(dir)
+		}
+
+		var matches []string
+		for _, i := range worktree.BestMatches(input, names) {
+			path := dirs[i]
+			if abs, err := filepath.Abs(path); err == nil {
+				path = abs
+			}
+			matches = append(matches, path)
+		}
+		if len(matches) > 0 {
+			logFuzzyMatch(input, matches)
+			return matches, nil
+		}
+	}
+	return nil, nil
+}
+
+// isFuzzyQuery reports whether input is a worktree name rather than a PR or issue reference.
+func isFuzzyQuery(input string) bool {
+	if _, err := strconv.Atoi(input); err == nil {
+		return false
+	}
+	if _, _, _, ok := ghwt.ParseShorthand(input); ok {
+		return false
+	}
+	worktreeType, err := ghwt.DetermineType(input)
+	return err == nil && worktreeType == worktree.Local
+}
+
+// logFuzzyMatch tells the user which worktree a unique fuzzy match picked. It writes to stderr so
+// that the output of switch can still be used with command substitution.
+func logFuzzyMatch(input string, matches []string) {
+	if len(matches) == 1 {
+		Log.Errf(logger.Cyan, "Using worktree '%s' for '%s'.\n", filepath.Base(matches[0]), input)
+	}
+}
+
+// metadataMatches returns the paths of the current repository's worktrees whose recorded
+// metadata matches one of the PR or issue candidates.
+func metadataMatches(candidates []*worktree.WorktreeInfo) []string {
+	if !slices.ContainsFunc(candidates, func(c *worktree.WorktreeInfo) bool { return c.Type != worktree.Local }) {
+		return nil
+	}
+	if !git.IsGitRepository(".") {
+		return nil
+	}
+	worktrees, err := git.GetWorktreeInfo()
+	if err != nil {
+		return nil
+	}
+
+	var matches []string
+	for _, wt := range worktrees {
+		md, err := worktree.ReadMetadata(wt.Path)
+		if err != nil || md == nil {
+			continue
+		}
+		for _, c := range candidates {
+			if c.Type == worktree.Local || c.Type != md.Type || c.Number != md.Number {
+				continue
+			}
+			if (c.Owner != "" && !strings.EqualFold(c.Owner, md.Owner)) || (c.Repo != "" && !strings.EqualFold(c.Repo, md.Repo)) {
+				continue
+			}
+			matches = append(matches, wt.Path)
+			break
+		}
+	}
+	return matches
+}
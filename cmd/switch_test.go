@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ffalor/gh-wt/internal/worktree"
+)
+
+func TestWorktreeCandidates(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []worktree.WorktreeInfo
+	}{
+		{"cli/cli#1", []worktree.WorktreeInfo{
+			{Type: worktree.PR, Owner: "cli", Repo: "cli", Number: 1, WorktreeName: "pr_1"},
+			{Type: worktree.Issue, Owner: "cli", Repo: "cli", Number: 1, WorktreeName: "issue_1"},
+		}},
+		{"#1", []worktree.WorktreeInfo{
+			{Type: worktree.PR, Number: 1, WorktreeName: "pr_1"},
+			{Type: worktree.Issue, Number: 1, WorktreeName: "issue_1"},
+		}},
+		{"1", []worktree.WorktreeInfo{
+			{Type: worktree.PR, Number: 1, WorktreeName: "pr_1"},
+			{Type: worktree.Issue, Number: 1, WorktreeName: "issue_1"},
+		}},
+		{"https://github.com/cli/cli/pull/2", []worktree.WorktreeInfo{
+			{Type: worktree.PR, Owner: "cli", Repo: "cli", Number: 2, WorktreeName: "pr_2"},
+		}},
+		{"https://github.com/cli/cli/issues/3", []worktree.WorktreeInfo{
+			{Type: worktree.Issue, Owner: "cli", Repo: "cli", Number: 3, WorktreeName: "issue_3"},
+		}},
+		{"feature/login", []worktree.WorktreeInfo{
+			{Type: worktree.Local, WorktreeName: "feature/login"},
+			{Type: worktree.Local, WorktreeName: "feature_login"},
+		}},
+	}
+	for _, tt := range tests {
+		candidates, err := worktreeCandidates(tt.input)
+		if err != nil {
+			t.Errorf("worktreeCandidates(%q) error = %v", tt.input, err)
+			continue
+		}
+		var got []worktree.WorktreeInfo
+		for _, candidate := range candidates {
+			got = append(got, *candidate)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("worktreeCandidates(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFindWorktreePathsAmbiguousNumber(t *testing.T) {
+	repo, base := setupRepo(t)
+	pr, issue := filepath.Join(base, "r", "pr_1"), filepath.Join(base, "r", "issue_1")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "pr_1", pr)
+	runGit(t, repo, "worktree", "add", "-q", "-b", "issue_1", issue)
+
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		// A number or #number can be either the PR or the issue, so both are returned.
+		{"1", []string{pr, issue}},
+		{"#1", []string{pr, issue}},
+		{"o/r#1", []string{pr, issue}},
+		{"pr_1", []string{pr}},
+	}
+	for _, tt := range tests {
+		got, err := findWorktreePaths(tt.input)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("findWorktreePaths(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
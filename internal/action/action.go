@@ -7,13 +7,13 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"runtime"
 	"strings"
 	"text/template"
 
 	"github.com/ffalor/gh-wt/internal/config"
 	"github.com/ffalor/gh-wt/internal/execext"
+	"github.com/ffalor/gh-wt/internal/git"
 	"github.com/ffalor/gh-wt/internal/logger"
 	"github.com/ffalor/gh-wt/internal/worktree"
 )
@@ -97,11 +97,11 @@ func Execute(ctx context.Context, opts *ExecuteOptions) error {
 	}
 
 	// Get git root directory
-	gitRoot, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	gitRoot, err := git.CommandStdout("rev-parse", "--show-toplevel")
 	if err != nil {
 		return fmt.Errorf("failed to get git root directory: %w", err)
 	}
-	rootDir := strings.TrimSpace(string(gitRoot))
+	rootDir := strings.TrimSpace(gitRoot)
 
 	// Prepare data for template
 	data := struct {
@@ -133,7 +133,9 @@ func Execute(ctx context.Context, opts *ExecuteOptions) error {
 		if err := tmpl.Execute(&renderedDir, data); err != nil {
 			return fmt.Errorf("failed to render action directory template: %w", err)
 		}
-		runDir = renderedDir.String()
+		if runDir, err = config.ExpandPath(renderedDir.String()); err != nil {
+			return err
+		}
 	}
 
 	opts.Logger.Outf(logger.Magenta, "\nRunning action '%s' in %s...\n", opts.ActionName, runDir)
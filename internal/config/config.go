@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -19,18 +23,129 @@ type Action struct {
 
 // Config holds the application configuration.
 type Config struct {
-	WorktreeBase string   `mapstructure:"worktree_dir"`
-	Actions      []Action `mapstructure:"actions"`
+	WorktreeBase          string        `mapstructure:"worktree_dir"`
+	WorktreeNameTemplate  string        `mapstructure:"worktree_name_template"`
+	WorktreePathTemplate  string        `mapstructure:"worktree_path_template"`
+	CopyFiles             []string      `mapstructure:"copy_files"`
+	CopyRespectGitignore  bool          `mapstructure:"copy_respect_gitignore"`
+	LinkDirs              []string      `mapstructure:"link_dirs"`
+	DefaultSparsePaths    []string      `mapstructure:"default_sparse_paths"`
+	GitBinary             string        `mapstructure:"git_binary"`
+	PostCreateHook        string        `mapstructure:"post_create_hook"`
+	Editor                string        `mapstructure:"editor"`
+	GitTimeout            time.Duration `mapstructure:"git_timeout"`
+	OnNameCollision       string        `mapstructure:"on_name_collision"`
+	OpenInTmux            bool          `mapstructure:"open_in_tmux"`
+	CloneProtocol         string        `mapstructure:"clone_protocol"`
+	CloneDefaultWorktree  bool          `mapstructure:"clone_default_worktree"`
+	FetchPrune            bool          `mapstructure:"fetch_prune"`
+	DirenvAllow           bool          `mapstructure:"direnv_allow"`
+	RecurseSubmodules     bool          `mapstructure:"recurse_submodules"`
+	PRBackend             string        `mapstructure:"pr_backend"`
+	DefaultExistingAction string        `mapstructure:"default_existing_action"`
+	IssueFromHead         bool          `mapstructure:"issue_from_head"`
+	DefaultRemote         string        `mapstructure:"default_remote"`
+	BranchPrefixPR        string        `mapstructure:"branch_prefix_pr"`
+	BranchPrefixIssue     string        `mapstructure:"branch_prefix_issue"`
+	BranchPrefixLocal     string        `mapstructure:"branch_prefix_local"`
+	Actions               []Action      `mapstructure:"actions"`
 }
 
+// Values for on_name_collision.
+const (
+	CollisionPrompt = "prompt"
+	CollisionSuffix = "suffix"
+	CollisionError  = "error"
+)
+
+// Values for clone_protocol.
+const (
+	ProtocolHTTPS = "https"
+	ProtocolSSH   = "ssh"
+)
+
+// Values for default_existing_action.
+const (
+	ExistingPrompt    = "prompt"
+	ExistingAttach    = "attach"
+	ExistingOverwrite = "overwrite"
+)
+
+// Values for pr_backend.
+const (
+	PRBackendGit = "git"
+	PRBackendGh  = "gh"
+)
+
 // Default values.
 const (
 	DefaultWorktreeBase = "~/github/worktree"
+	DefaultGitTimeout   = 60 * time.Second
+	DefaultRemote       = "origin"
 	ConfigName          = "config"
 	ConfigType          = "yaml"
+	RepoConfigName      = ".gh-worktree.yaml"
 )
 
-var v *viper.Viper
+var (
+	v              *viper.Viper
+	repoConfigFile string
+)
+
+// Dir returns the directory holding the config file.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "gh-wt"), nil
+}
+
+// File returns the path of the config file.
+func File() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ConfigName+"."+ConfigType), nil
+}
+
+// Keys returns the top-level configuration keys.
+func Keys() []string {
+	t := reflect.TypeFor[Config]()
+	keys := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		keys = append(keys, t.Field(i).Tag.Get("mapstructure"))
+	}
+	return keys
+}
+
+// ParseValue converts a command line value for key into the type stored in the config file.
+// List keys take a comma-separated string. Keys holding structured values cannot be parsed.
+func ParseValue(key, raw string) (any, error) {
+	t := reflect.TypeFor[Config]()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if f.Tag.Get("mapstructure") != key {
+			continue
+		}
+		switch {
+		case f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.String:
+			var items []string
+			for _, item := range strings.Split(raw, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			return items, nil
+		case f.Type.Kind() == reflect.Slice || f.Type.Kind() == reflect.Struct:
+			return nil, fmt.Errorf("'%s' cannot be set from the command line; edit the config file instead", key)
+		default:
+			return raw, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown config key '%s'; valid keys: %s", key, strings.Join(Keys(), ", "))
+}
 
 // Load initializes Viper and reads the configuration.
 // It returns the loaded Viper instance and handles file-not-found gracefully.
@@ -42,7 +157,10 @@ func Load() (*viper.Viper, error) {
 		return nil, fmt.Errorf("cannot determine home directory: %w", err)
 	}
 
-	configDir := filepath.Join(home, ".config", "gh-wt")
+	configDir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
 
 	v.AddConfigPath(configDir)
 
@@ -53,8 +171,30 @@ func Load() (*viper.Viper, error) {
 	v.SetEnvPrefix("GH_WT")
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
+	// Bind every key explicitly so environment variables apply even when the key
+	// is missing from the config file. GH_WORKTREE_* is accepted as well as GH_WT_*.
+	for _, key := range Keys() {
+		envKey := strings.ToUpper(key)
+		if err := v.BindEnv(key, "GH_WT_"+envKey, "GH_WORKTREE_"+envKey); err != nil {
+			return nil, fmt.Errorf("failed to bind environment for %s: %w", key, err)
+		}
+	}
+	if err := v.BindEnv("worktree_dir", "GH_WT_WORKTREE_DIR", "GH_WORKTREE_WORKTREE_DIR", "GH_WORKTREE_BASE"); err != nil {
+		return nil, fmt.Errorf("failed to bind environment for worktree_dir: %w", err)
+	}
+	if err := v.BindEnv("git_binary", "GH_WT_GIT_BINARY", "GH_WORKTREE_GIT_BINARY", "GH_WORKTREE_GIT"); err != nil {
+		return nil, fmt.Errorf("failed to bind environment for git_binary: %w", err)
+	}
+
 	// Sensible defaults
 	v.SetDefault("worktree_dir", filepath.Join(home, "github", "worktree"))
+	v.SetDefault("git_timeout", DefaultGitTimeout)
+	v.SetDefault("on_name_collision", CollisionPrompt)
+	v.SetDefault("copy_respect_gitignore", true)
+	v.SetDefault("clone_default_worktree", true)
+	v.SetDefault("default_remote", DefaultRemote)
+	v.SetDefault("pr_backend", PRBackendGit)
+	v.SetDefault("default_existing_action", ExistingPrompt)
 
 	if err := v.ReadInConfig(); err != nil {
 		var notFound viper.ConfigFileNotFoundError
@@ -66,6 +206,69 @@ func Load() (*viper.Viper, error) {
 	return v, nil
 }
 
+// RepoKeys are the keys a repository config file may set. Repositories are not trusted, so keys
+// that run commands, such as post_create_hook, actions, git_binary, and direnv_allow, or that copy,
+// link, or overwrite files and branches are left to the global config.
+var RepoKeys = []string{
+	"worktree_dir",
+	"worktree_name_template",
+	"worktree_path_template",
+	"on_name_collision",
+	"default_remote",
+	"default_sparse_paths",
+	"branch_prefix_pr",
+	"branch_prefix_issue",
+	"branch_prefix_local",
+}
+
+// LoadRepo merges the first repository config file found in dir or one of its parents
+// over the global configuration. Keys set in the repository config win, unset keys
+// keep their global values. Keys other than RepoKeys are not merged and are returned
+// as ignored. Load must be called first.
+func LoadRepo(dir string) (ignored []string, err error) {
+	if v == nil {
+		return nil, errors.New("config not initialized; call Load first")
+	}
+
+	for {
+		candidate := filepath.Join(dir, RepoConfigName)
+		if _, err := os.Stat(candidate); err == nil {
+			repo := viper.New()
+			repo.SetConfigFile(candidate)
+			repo.SetConfigType(ConfigType)
+			if err := repo.ReadInConfig(); err != nil {
+				return nil, fmt.Errorf("failed to parse repository config %s: %w", candidate, err)
+			}
+
+			allowed := make(map[string]any)
+			for key, value := range repo.AllSettings() {
+				if slices.Contains(RepoKeys, key) {
+					allowed[key] = value
+				} else {
+					ignored = append(ignored, key)
+				}
+			}
+			if err := v.MergeConfigMap(allowed); err != nil {
+				return nil, fmt.Errorf("failed to merge repository config %s: %w", candidate, err)
+			}
+			slices.Sort(ignored)
+			repoConfigFile = candidate
+			return ignored, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// RepoConfigFileUsed returns the path of the merged repository config file (or "" if none).
+func RepoConfigFileUsed() string {
+	return repoConfigFile
+}
+
 // Save persists the current Viper state to the config file.
 // Creates directories and file if needed.
 func Save() error {
@@ -75,23 +278,49 @@ func Save() error {
 
 	configFile := v.ConfigFileUsed()
 	if configFile == "" {
-		home, _ := os.UserHomeDir()
-		configDir := filepath.Join(home, ".config", "gh-worktree")
-		configFile = filepath.Join(configDir, "config.yaml")
+		var err error
+		configFile, err = File()
+		if err != nil {
+			return err
+		}
 
 		if err := os.MkdirAll(filepath.Dir(configFile), 0o755); err != nil {
 			return fmt.Errorf("cannot create config directory: %w", err)
 		}
 	}
 
-	// SafeWriteConfigAs creates if missing, refuses to overwrite unexpectedly
-	if err := v.SafeWriteConfigAs(configFile); err != nil {
-		if os.IsNotExist(err) {
-			return v.WriteConfigAs(configFile)
+	if err := v.WriteConfigAs(configFile); err != nil {
+		return fmt.Errorf("failed to write config to %s: %w", configFile, err)
+	}
+
+	return nil
+}
+
+// SaveValue sets key in the config file and writes it, leaving other values in the file untouched.
+// Unlike Save, defaults and environment overrides are not written.
+func SaveValue(key string, value any) error {
+	configFile, err := File()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(configFile), 0o755); err != nil {
+		return fmt.Errorf("cannot create config directory: %w", err)
+	}
+
+	fileOnly := viper.New()
+	fileOnly.SetConfigFile(configFile)
+	if _, err := os.Stat(configFile); err == nil {
+		if err := fileOnly.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to parse config file: %w", err)
 		}
+	}
+
+	fileOnly.Set(key, value)
+	if err := fileOnly.WriteConfigAs(configFile); err != nil {
 		return fmt.Errorf("failed to write config to %s: %w", configFile, err)
 	}
 
+	Set(key, value)
 	return nil
 }
 
@@ -107,18 +336,88 @@ func Get() (Config, error) {
 		return Config{}, fmt.Errorf("cannot unmarshal config: %w", err)
 	}
 
-	// Expand tilde in WorktreeBase if present
-	if strings.HasPrefix(cfg.WorktreeBase, "~/") {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return Config{}, fmt.Errorf("cannot determine home directory: %w", err)
-		}
-		cfg.WorktreeBase = filepath.Join(home, cfg.WorktreeBase[2:])
+	var err error
+	if cfg.WorktreeBase, err = ExpandPath(cfg.WorktreeBase); err != nil {
+		return Config{}, err
+	}
+	if cfg.GitBinary, err = ExpandPath(cfg.GitBinary); err != nil {
+		return Config{}, err
+	}
+	// Copied and linked paths must stay inside the repository, so only variables are expanded, not ~.
+	cfg.CopyFiles = expandEnvAll(cfg.CopyFiles)
+	cfg.LinkDirs = expandEnvAll(cfg.LinkDirs)
+
+	switch cfg.OnNameCollision {
+	case CollisionPrompt, CollisionSuffix, CollisionError:
+	default:
+		return Config{}, fmt.Errorf("invalid on_name_collision %q: must be %s, %s, or %s",
+			cfg.OnNameCollision, CollisionPrompt, CollisionSuffix, CollisionError)
+	}
+
+	switch cfg.CloneProtocol {
+	case "", ProtocolHTTPS, ProtocolSSH:
+	default:
+		return Config{}, fmt.Errorf("invalid clone_protocol %q: must be %s or %s", cfg.CloneProtocol, ProtocolHTTPS, ProtocolSSH)
+	}
+
+	switch cfg.DefaultExistingAction {
+	case ExistingPrompt, ExistingAttach, ExistingOverwrite:
+	default:
+		return Config{}, fmt.Errorf("invalid default_existing_action %q: must be %s, %s, or %s",
+			cfg.DefaultExistingAction, ExistingPrompt, ExistingAttach, ExistingOverwrite)
+	}
+
+	switch cfg.PRBackend {
+	case PRBackendGit, PRBackendGh:
+	default:
+		return Config{}, fmt.Errorf("invalid pr_backend %q: must be %s or %s", cfg.PRBackend, PRBackendGit, PRBackendGh)
 	}
 
 	return cfg, nil
 }
 
+// windowsEnvVar matches Windows-style variable references such as %USERPROFILE%.
+var windowsEnvVar = regexp.MustCompile(`%([A-Za-z_][A-Za-z0-9_]*)%`)
+
+// ExpandEnv expands environment variables written as $NAME, ${NAME}, or %NAME% in s.
+// Like cmd.exe, it keeps %NAME% references to variables that are not set.
+func ExpandEnv(s string) string {
+	s = os.ExpandEnv(s)
+	return windowsEnvVar.ReplaceAllStringFunc(s, func(ref string) string {
+		if value, ok := os.LookupEnv(ref[1 : len(ref)-1]); ok {
+			return value
+		}
+		return ref
+	})
+}
+
+// expandEnvAll returns a copy of values with environment variables expanded (see ExpandEnv).
+func expandEnvAll(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	expanded := make([]string, len(values))
+	for i, value := range values {
+		expanded[i] = ExpandEnv(value)
+	}
+	return expanded
+}
+
+// ExpandPath expands environment variables (see ExpandEnv) and a leading ~ in path.
+func ExpandPath(path string) (string, error) {
+	path = ExpandEnv(path)
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok || (rest != "" && !os.IsPathSeparator(rest[0])) {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(home, rest), nil
+}
+
 // Set updates a value in the Viper store (in memory only).
 // Call Save() afterward to persist changes.
 func Set(key string, value any) {
@@ -127,6 +426,14 @@ func Set(key string, value any) {
 	}
 }
 
+// GetValue returns the effective value of key, including defaults and environment overrides.
+func GetValue(key string) any {
+	if v != nil {
+		return v.Get(key)
+	}
+	return nil
+}
+
 // ConfigFileUsed returns the path of the loaded config file (or "" if none).
 func ConfigFileUsed() string {
 	if v != nil {
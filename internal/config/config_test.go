@@ -0,0 +1,212 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// loadTestConfig points the home directory at a temporary directory, writes global as the
+// global config file, and loads it.
+func loadTestConfig(t *testing.T, global string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	if global != "" {
+		writeFile(t, filepath.Join(home, ".config", "gh-wt", ConfigName+"."+ConfigType), global)
+	}
+	if _, err := Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadRepoPrecedence(t *testing.T) {
+	loadTestConfig(t, `
+worktree_name_template: "global_{number}"
+default_remote: upstream
+post_create_hook: "echo global"
+`)
+
+	repoDir := t.TempDir()
+	writeFile(t, filepath.Join(repoDir, RepoConfigName), `
+worktree_name_template: "repo_{number}"
+on_name_collision: suffix
+post_create_hook: "echo repo"
+actions:
+  - name: evil
+    cmds: ["echo evil"]
+`)
+	subDir := filepath.Join(repoDir, "sub", "dir")
+	if err := os.MkdirAll(subDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// The repository config is found from a subdirectory too.
+	ignored, err := LoadRepo(subDir)
+	if err != nil {
+		t.Fatalf("LoadRepo() error = %v", err)
+	}
+	if want := []string{"actions", "post_create_hook"}; !slices.Equal(ignored, want) {
+		t.Errorf("LoadRepo() ignored = %v, want %v", ignored, want)
+	}
+	if got, want := RepoConfigFileUsed(), filepath.Join(repoDir, RepoConfigName); got != want {
+		t.Errorf("RepoConfigFileUsed() = %q, want %q", got, want)
+	}
+
+	cfg, err := Get()
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"repository value wins", cfg.WorktreeNameTemplate, "repo_{number}"},
+		{"repository value over default", cfg.OnNameCollision, CollisionSuffix},
+		{"unset key keeps global value", cfg.DefaultRemote, "upstream"},
+		{"untrusted key keeps global value", cfg.PostCreateHook, "echo global"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+	if len(cfg.Actions) != 0 {
+		t.Errorf("actions from the repository config were merged: %v", cfg.Actions)
+	}
+}
+
+func TestLoadRepoWithoutFile(t *testing.T) {
+	loadTestConfig(t, `default_remote: upstream`)
+
+	ignored, err := LoadRepo(t.TempDir())
+	if err != nil || ignored != nil {
+		t.Fatalf("LoadRepo() = %v, %v; want nil, nil", ignored, err)
+	}
+	cfg, err := Get()
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if cfg.DefaultRemote != "upstream" {
+		t.Errorf("DefaultRemote = %q, want %q", cfg.DefaultRemote, "upstream")
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("GH_WT_TEST_DIR", "projects")
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~", home},
+		{"~/sub", filepath.Join(home, "sub")},
+		{"~/sub/../other", filepath.Join(home, "other")},
+		{"$HOME/x", home + "/x"},
+		{"${HOME}/x", home + "/x"},
+		{"%USERPROFILE%/x", home + "/x"},
+		{"~/$GH_WT_TEST_DIR", filepath.Join(home, "projects")},
+		{"%GH_WT_TEST_DIR%/%GH_WT_TEST_UNSET%", "projects/%GH_WT_TEST_UNSET%"},
+		{"~user/x", "~user/x"},
+		{"a/~/b", "a/~/b"},
+		{"relative/path", "relative/path"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, err := ExpandPath(tt.path)
+		if err != nil || got != tt.want {
+			t.Errorf("ExpandPath(%q) = %q, %v; want %q", tt.path, got, err, tt.want)
+		}
+	}
+}
+
+func TestGetExpandsPaths(t *testing.T) {
+	t.Setenv("GH_WT_TEST_DIR", "conf")
+	loadTestConfig(t, `
+worktree_dir: "~/worktrees"
+git_binary: "$HOME/bin/git"
+copy_files: ["$GH_WT_TEST_DIR/.env", "~/.env"]
+link_dirs: ["%GH_WT_TEST_DIR%/shared"]
+`)
+	home, _ := os.UserHomeDir()
+
+	cfg, err := Get()
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if want := filepath.Join(home, "worktrees"); cfg.WorktreeBase != want {
+		t.Errorf("WorktreeBase = %q, want %q", cfg.WorktreeBase, want)
+	}
+	if want := home + "/bin/git"; cfg.GitBinary != want {
+		t.Errorf("GitBinary = %q, want %q", cfg.GitBinary, want)
+	}
+	// ~ is kept in copied paths, which must be relative to the repository.
+	if want := []string{"conf/.env", "~/.env"}; !slices.Equal(cfg.CopyFiles, want) {
+		t.Errorf("CopyFiles = %q, want %q", cfg.CopyFiles, want)
+	}
+	if want := []string{"conf/shared"}; !slices.Equal(cfg.LinkDirs, want) {
+		t.Errorf("LinkDirs = %q, want %q", cfg.LinkDirs, want)
+	}
+}
+
+func TestPrecedence(t *testing.T) {
+	global := `
+default_remote: file
+editor: file
+branch_prefix_pr: file
+branch_prefix_issue: file
+worktree_name_template: file
+`
+	tests := []struct {
+		name string
+		key  string
+		env  map[string]string
+		set  string
+		want string
+	}{
+		{"default", "on_name_collision", nil, "", CollisionPrompt},
+		{"file beats default", "default_remote", nil, "", "file"},
+		{"GH_WT_ env beats file", "editor", map[string]string{"GH_WT_EDITOR": "env"}, "", "env"},
+		{"GH_WORKTREE_ env beats file", "editor", map[string]string{"GH_WORKTREE_EDITOR": "env"}, "", "env"},
+		{"GH_WT_ beats GH_WORKTREE_", "editor", map[string]string{"GH_WT_EDITOR": "wt", "GH_WORKTREE_EDITOR": "worktree"}, "", "wt"},
+		{"legacy alias beats file", "worktree_dir", map[string]string{"GH_WORKTREE_BASE": "/env"}, "", "/env"},
+		{"Set beats file", "branch_prefix_pr", nil, "flag", "flag"},
+		{"Set beats env", "branch_prefix_issue", map[string]string{"GH_WT_BRANCH_PREFIX_ISSUE": "env"}, "flag", "flag"},
+		{"env beats repository file", "worktree_name_template", map[string]string{"GH_WT_WORKTREE_NAME_TEMPLATE": "env"}, "", "env"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			loadTestConfig(t, global)
+			repoDir := t.TempDir()
+			writeFile(t, filepath.Join(repoDir, RepoConfigName), "worktree_name_template: repo\n")
+			if _, err := LoadRepo(repoDir); err != nil {
+				t.Fatalf("LoadRepo() error = %v", err)
+			}
+			if tt.set != "" {
+				Set(tt.key, tt.set)
+			}
+			if got := GetValue(tt.key); got != tt.want {
+				t.Errorf("%s = %v, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package direnv
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// EnvrcName is the file direnv loads the environment of a directory from.
+const EnvrcName = ".envrc"
+
+// Installed reports whether the direnv binary is on PATH.
+func Installed() bool {
+	_, err := exec.LookPath("direnv")
+	return err == nil
+}
+
+// HasEnvrc reports whether dir contains an .envrc file.
+func HasEnvrc(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, EnvrcName))
+	return err == nil
+}
+
+// Allow marks the .envrc in dir as trusted so that direnv loads it without asking.
+func Allow(dir string) error {
+	cmd := exec.Command("direnv", "allow", dir)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("direnv allow failed: %s", msg)
+		}
+		return fmt.Errorf("direnv allow failed: %w", err)
+	}
+	return nil
+}
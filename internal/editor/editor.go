@@ -0,0 +1,59 @@
+package editor
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DefaultEditor is used when no editor is configured.
+const DefaultEditor = "code"
+
+// ErrNoEditor is returned when no usable editor can be found.
+var ErrNoEditor = errors.New("no editor found")
+
+// terminalEditors run in the current terminal and must be waited on.
+var terminalEditors = []string{"vi", "vim", "nvim", "nano", "emacs", "hx", "helix", "micro", "kak"}
+
+// Resolve returns the editor command to use.
+// The configured editor wins, then $EDITOR, then DefaultEditor.
+func Resolve(configured string) string {
+	for _, candidate := range []string{configured, os.Getenv("EDITOR")} {
+		if strings.TrimSpace(candidate) != "" {
+			return candidate
+		}
+	}
+	return DefaultEditor
+}
+
+// Open opens path in the given editor command, which may include arguments.
+// GUI editors are started in the background; terminal editors take over the terminal.
+func Open(editorCmd, path string) error {
+	fields := strings.Fields(editorCmd)
+	if len(fields) == 0 {
+		return ErrNoEditor
+	}
+
+	bin, err := exec.LookPath(fields[0])
+	if err != nil {
+		return ErrNoEditor
+	}
+
+	cmd := exec.Command(bin, append(fields[1:], path)...)
+	cmd.Dir = path
+
+	if slices.Contains(terminalEditors, filepath.Base(fields[0])) {
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
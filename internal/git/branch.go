@@ -1,10 +1,20 @@
 package git
 
 import (
-	"os/exec"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// IsValidRefName reports whether name is a valid branch name according to git check-ref-format --branch.
+func IsValidRefName(name string) bool {
+	return CommandSilent("check-ref-format", "--branch", name) == nil
+}
+
 // BranchDelete deletes a branch.
 func BranchDelete(branch string, force bool) error {
 	args := []string{"branch", "-d"}
@@ -12,16 +22,67 @@ func BranchDelete(branch string, force bool) error {
 		args[1] = "-D"
 	}
 	args = append(args, branch)
-	return Command(args...)
+	return CommandCapture(args...)
+}
+
+// BranchRename renames a branch, including in worktrees that have it checked out.
+func BranchRename(oldName, newName string) error {
+	return CommandCapture("branch", "-m", oldName, newName)
 }
 
 // BranchExists checks if a branch exists in the repository.
 func BranchExists(branch string) bool {
-	cmd := exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+branch)
+	cmd := newCommand(context.Background(), "show-ref", "--verify", "--quiet", "refs/heads/"+branch)
+	err := cmd.Run()
+	return err == nil
+}
+
+// RemoteBranchExists checks if a remote-tracking branch exists for branch on remote.
+func RemoteBranchExists(remote, branch string) bool {
+	cmd := newCommand(context.Background(), "show-ref", "--verify", "--quiet", "refs/remotes/"+remote+"/"+branch)
 	err := cmd.Run()
 	return err == nil
 }
 
+// Remotes returns the names of the configured remotes.
+func Remotes() ([]string, error) {
+	out, err := CommandOutput("remote")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+// DefaultBranch returns the remote-tracking ref of the default branch of remote, such as origin/main.
+// It relies on refs/remotes/<remote>/HEAD, which is set by clone or 'git remote set-head'.
+func DefaultBranch(remote string) (string, error) {
+	out, err := CommandOutput("symbolic-ref", "--quiet", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err != nil {
+		return "", fmt.Errorf("default branch of %s is unknown", remote)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// SetRemoteHead records branch as the default branch of remote, like 'git remote set-head' does,
+// without contacting the remote.
+func SetRemoteHead(remote, branch string) error {
+	return CommandSilent("symbolic-ref", "refs/remotes/"+remote+"/HEAD", "refs/remotes/"+remote+"/"+branch)
+}
+
+// SetUpstream configures branch to track ref on remote, so pull and push use it.
+// remote may be a configured remote name or a URL.
+func SetUpstream(branch, remote, ref string) error {
+	if err := CommandSilent("config", "branch."+branch+".remote", remote); err != nil {
+		return err
+	}
+	return CommandSilent("config", "branch."+branch+".merge", "refs/heads/"+ref)
+}
+
+// VerifyRef checks that ref resolves to a commit in the repository.
+func VerifyRef(ref string) error {
+	return CommandSilent("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+}
+
 // GetCurrentBranch returns the current branch name in the specified directory.
 func GetCurrentBranch(path string) (string, error) {
 	out, err := CommandOutputAt(path, "rev-parse", "--abbrev-ref", "HEAD")
@@ -39,3 +100,152 @@ func GetCurrentBranchAtCwd() (string, error) {
 	}
 	return strings.TrimSpace(out), nil
 }
+
+// ErrNoUpstream is returned when the branch checked out at a path has no upstream.
+var ErrNoUpstream = errors.New("no upstream configured")
+
+// Upstream returns the short name of the upstream of HEAD at path, such as origin/main.
+// ErrNoUpstream is returned when HEAD has no upstream branch.
+func Upstream(path string) (string, error) {
+	out, err := CommandOutputAt(path, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
+	if err != nil {
+		return "", ErrNoUpstream
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// AheadBehind returns how many commits HEAD at path is ahead of and behind its upstream.
+// ErrNoUpstream is returned when HEAD has no upstream branch.
+func AheadBehind(path string) (ahead, behind int, err error) {
+	if _, err := Upstream(path); err != nil {
+		return 0, 0, err
+	}
+
+	out, err := CommandOutputAt(path, "rev-list", "--left-right", "--count", "@{u}...HEAD")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", out)
+	}
+	if behind, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, err
+	}
+	if ahead, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+// CommitsAhead returns the number of commits HEAD at path has that base does not.
+func CommitsAhead(path, base string) (int, error) {
+	out, err := CommandOutputAt(path, "rev-list", "--count", base+"..HEAD")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(out))
+}
+
+// UnmergedCommits returns the commits of branch that are reachable neither from HEAD nor from
+// any remote-tracking branch, which are lost when branch is deleted. It returns their count and
+// the one-line summaries of up to maxCommits of them.
+func UnmergedCommits(branch string, maxCommits int) (int, []string, error) {
+	ref := "refs/heads/" + branch
+	out, err := CommandOutput("rev-list", "--count", ref, "--not", "HEAD", "--remotes")
+	if err != nil {
+		return 0, nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil || count == 0 {
+		return count, nil, err
+	}
+
+	out, err = CommandOutput("log", "--oneline", "--no-decorate", fmt.Sprintf("--max-count=%d", maxCommits), ref, "--not", "HEAD", "--remotes")
+	if err != nil {
+		return count, nil, err
+	}
+	return count, strings.Split(strings.TrimSpace(out), "\n"), nil
+}
+
+// CommitEmpty creates an empty commit with message at path.
+func CommitEmpty(path, message string) error {
+	out, err := CommandOutputAt(path, "commit", "--allow-empty", "--quiet", "-m", message)
+	if err != nil {
+		return fmt.Errorf("%s", gitMessage(out))
+	}
+	return nil
+}
+
+// PushUpstream pushes branch to remote and sets it as the branch's upstream.
+func PushUpstream(ctx context.Context, remote, branch string) error {
+	return CommandCaptureContext(ctx, "push", "--set-upstream", remote, branch)
+}
+
+// LastCommitSubject returns the subject of the commit checked out at path.
+func LastCommitSubject(path string) (string, error) {
+	out, err := CommandOutputAt(path, "log", "-1", "--format=%s")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// LastCommitTime returns the committer date of the commit checked out at path.
+func LastCommitTime(path string) (time.Time, error) {
+	out, err := CommandOutputAt(path, "log", "-1", "--format=%ct")
+	if err != nil {
+		return time.Time{}, err
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected commit date %q", out)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// RefKind classifies what a name refers to in the repository.
+type RefKind string
+
+const (
+	RefNone   RefKind = ""
+	RefBranch RefKind = "branch"
+	RefTag    RefKind = "tag"
+	RefCommit RefKind = "sha"
+)
+
+var shaPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// ResolveRef reports whether name is a local branch, a tag, or a commit SHA.
+// PR and issue references are URLs and are classified by the caller.
+func ResolveRef(name string) RefKind {
+	switch {
+	case BranchExists(name):
+		return RefBranch
+	case CommandSilent("show-ref", "--verify", "--quiet", "refs/tags/"+name) == nil:
+		return RefTag
+	case shaPattern.MatchString(name) && VerifyRef(name) == nil:
+		return RefCommit
+	default:
+		return RefNone
+	}
+}
+
+// CommitSHA returns the full commit hash ref points to.
+func CommitSHA(ref string) (string, error) {
+	out, err := CommandOutput("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// ShortSHA returns the abbreviated commit hash ref points to.
+func ShortSHA(ref string) (string, error) {
+	out, err := CommandOutput("rev-parse", "--short", ref+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
@@ -0,0 +1,41 @@
+package git
+
+import "testing"
+
+func TestIsValidRefName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"feature", true},
+		{"feature/login", true},
+		{"bug/ISSUE-1/fix", true},
+		{"pr_123", true},
+		{"ünïcödé", true},
+		{"with space", false},
+		{"a..b", false},
+		{"..", false},
+		{".hidden", false},
+		{"feature/.hidden", false},
+		{"branch.lock", false},
+		{"feature/branch.lock", false},
+		{"-leading-dash", false},
+		{"trailing/", false},
+		{"double//slash", false},
+		{"tilde~1", false},
+		{"caret^", false},
+		{"colon:name", false},
+		{"question?", false},
+		{"star*", false},
+		{"open[bracket", false},
+		{"back\\slash", false},
+		{"at@{brace", false},
+		{"trailing.", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsValidRefName(tt.name); got != tt.want {
+			t.Errorf("IsValidRefName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
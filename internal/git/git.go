@@ -1,76 +1,401 @@
 package git
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/term"
 )
 
+// Quiet hides the output of git commands that succeed.
+var Quiet bool
+
+// Binary is the git executable every command runs. It can be set to a path to pin a specific git.
+var Binary = "git"
+
+// TraceOutput, when set, receives every git command line before it runs.
+var TraceOutput io.Writer
+
+// newCommand returns a git command, tracing it to TraceOutput when set.
+func newCommand(ctx context.Context, args ...string) *exec.Cmd {
+	if TraceOutput != nil {
+		fmt.Fprintf(TraceOutput, "+ %s\n", FormatCommand(Binary, args))
+	}
+	return exec.CommandContext(ctx, Binary, args...)
+}
+
+// FormatCommand joins a command line for display, redacting credentials in URL arguments.
+func FormatCommand(name string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, name)
+	for _, arg := range args {
+		// Tokens may be embedded as the user or password of https URLs.
+		if u, err := url.Parse(arg); err == nil && u.User != nil {
+			u.User = url.User("***")
+			arg = u.String()
+		}
+		if arg == "" || strings.ContainsAny(arg, " \t\"'") {
+			arg = strconv.Quote(arg)
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
 // Command runs a git command in the current directory.
 func Command(args ...string) error {
-	cmd := exec.Command("git", args...)
+	return CommandContext(context.Background(), args...)
+}
+
+// CommandContext runs a git command in the current directory and stops it when ctx is done.
+func CommandContext(ctx context.Context, args ...string) error {
+	if Quiet {
+		return CommandCaptureContext(ctx, args...)
+	}
+	cmd := newCommand(ctx, args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return contextError(ctx, cmd.Run(), args)
+}
+
+// Error is returned by CommandCapture when git fails. It carries git's stderr
+// so callers can show the actual reason instead of just the exit status.
+type Error struct {
+	Args   []string
+	Stderr string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Stderr == "" {
+		return e.Err.Error()
+	}
+	return e.Stderr
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// CommandCapture runs a git command in the current directory, capturing its stderr.
+func CommandCapture(args ...string) error {
+	return CommandCaptureContext(context.Background(), args...)
+}
+
+// CommandCaptureContext runs a git command and stops it when ctx is done.
+// Stdout is streamed to the terminal. Stderr is replayed to the terminal on success
+// and returned in an *Error on failure. With Quiet, output of successful commands is dropped.
+func CommandCaptureContext(ctx context.Context, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := newCommand(ctx, args...)
+	if !Quiet {
+		cmd.Stdout = os.Stdout
+	}
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err == nil {
+		if !Quiet {
+			_, _ = os.Stderr.Write(stderr.Bytes())
+		}
+		return nil
+	}
+	if ctx.Err() != nil {
+		return contextError(ctx, err, args)
+	}
+	return &Error{
+		Args:   args,
+		Stderr: gitMessage(stderr.String()),
+		Err:    err,
+	}
+}
+
+// CommandStreamContext runs a long-running git command such as clone and stops it when ctx is
+// done. Unlike CommandCaptureContext, stderr is streamed to the terminal while the command runs,
+// so progress is visible, and is also kept so that a failure returns an *Error with git's
+// message. With Quiet it behaves like CommandCaptureContext.
+func CommandStreamContext(ctx context.Context, args ...string) error {
+	if Quiet {
+		return CommandCaptureContext(ctx, args...)
+	}
+	var stderr bytes.Buffer
+	cmd := newCommand(ctx, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+
+	err := cmd.Run()
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return contextError(ctx, err, args)
+	}
+	return &Error{
+		Args:   args,
+		Stderr: gitMessage(stderr.String()),
+		Err:    err,
+	}
+}
+
+// gitMessage trims git's stderr to the first "fatal: " or "error: " line onwards,
+// skipping progress output such as "Preparing worktree", and drops that prefix.
+func gitMessage(stderr string) string {
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	for i, line := range lines {
+		for _, prefix := range []string{"fatal: ", "error: "} {
+			if rest, ok := strings.CutPrefix(line, prefix); ok {
+				lines[i] = rest
+				return strings.Join(lines[i:], "\n")
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
 // CommandSilent runs a git command without output in the current directory.
 func CommandSilent(args ...string) error {
-	cmd := exec.Command("git", args...)
-	return cmd.Run()
+	return CommandSilentContext(context.Background(), args...)
+}
+
+// CommandSilentContext runs a git command without output and stops it when ctx is done.
+func CommandSilentContext(ctx context.Context, args ...string) error {
+	cmd := newCommand(ctx, args...)
+	return contextError(ctx, cmd.Run(), args)
 }
 
 // CommandOutput runs a git command and returns the output from current directory.
 func CommandOutput(args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
+	return CommandOutputContext(context.Background(), args...)
+}
+
+// CommandOutputContext runs a git command and returns the output, stopping it when ctx is done.
+func CommandOutputContext(ctx context.Context, args ...string) (string, error) {
+	cmd := newCommand(ctx, args...)
 	out, err := cmd.CombinedOutput()
-	return string(out), err
+	return string(out), contextError(ctx, err, args)
+}
+
+// CommandStdout runs a git command in the current directory and returns only its stdout, so that
+// warnings and traces on stderr cannot end up in values such as paths. Stderr is returned in an
+// *Error on failure.
+func CommandStdout(args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := newCommand(context.Background(), args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", &Error{Args: args, Stderr: gitMessage(stderr.String()), Err: err}
+	}
+	return string(out), nil
 }
 
 // CommandOutputAt runs a git command and returns the output from specified directory.
 func CommandOutputAt(path string, args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
+	return CommandOutputAtContext(context.Background(), path, args...)
+}
+
+// CommandOutputAtContext runs a git command in the specified directory and returns the output,
+// stopping it when ctx is done.
+func CommandOutputAtContext(ctx context.Context, path string, args ...string) (string, error) {
+	cmd := newCommand(ctx, args...)
 	cmd.Dir = path
 	out, err := cmd.CombinedOutput()
-	return string(out), err
+	return string(out), contextError(ctx, err, args)
+}
+
+// contextError replaces the error of a killed git process with one naming the cancelled operation.
+func contextError(ctx context.Context, err error, args []string) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	op := "git"
+	if len(args) > 0 {
+		op = "git " + args[0]
+	}
+	if len(args) > 1 && args[0] == "worktree" {
+		op += " " + args[1]
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s timed out: %w", op, ctx.Err())
+	}
+	return fmt.Errorf("%s cancelled: %w", op, ctx.Err())
 }
 
 // WorktreeAdd adds a worktree with a new branch.
-func WorktreeAdd(branch, worktreePath string) error {
-	return Command("worktree", "add", "-b", branch, worktreePath)
+func WorktreeAdd(ctx context.Context, branch, worktreePath string) error {
+	return CommandCaptureContext(ctx, "worktree", "add", "-b", branch, worktreePath)
+}
+
+// TrackMode selects whether a new branch tracks its start point, like git branch --track and --no-track.
+type TrackMode int
+
+const (
+	// TrackDefault leaves the choice to git's branch.autoSetupMerge setting.
+	TrackDefault TrackMode = iota
+	// TrackAlways makes the new branch track its start point, which must be a branch.
+	TrackAlways
+	// TrackNever creates the branch without upstream tracking.
+	TrackNever
+)
+
+// WorktreeAddFromRef adds a worktree with a new branch starting at ref.
+func WorktreeAddFromRef(ctx context.Context, branch, worktreePath, ref string, track TrackMode) error {
+	args := []string{"worktree", "add"}
+	switch track {
+	case TrackAlways:
+		args = append(args, "--track")
+	case TrackNever:
+		args = append(args, "--no-track")
+	}
+	args = append(args, "-b", branch, worktreePath, ref)
+	return CommandCaptureContext(ctx, args...)
+}
+
+// SparseCheckout limits the working tree at worktreePath to the given directories using cone mode.
+func SparseCheckout(ctx context.Context, worktreePath string, dirs []string) error {
+	if err := RequireVersion(MinVersionSparseCone, "sparse-checkout"); err != nil {
+		return err
+	}
+	args := append([]string{"-C", worktreePath, "sparse-checkout", "set", "--cone"}, dirs...)
+	return CommandCaptureContext(ctx, args...)
 }
 
-// WorktreeAddFromRef adds a worktree from a specific ref.
-func WorktreeAddFromRef(branch, worktreePath, ref string) error {
-	return Command("worktree", "add", "-b", branch, worktreePath, ref)
+// WorktreeAddDetached adds a worktree with a detached HEAD at ref, without creating a branch.
+func WorktreeAddDetached(ctx context.Context, worktreePath, ref string) error {
+	return CommandCaptureContext(ctx, "worktree", "add", "--detach", worktreePath, ref)
 }
 
 // WorktreeAddFromBranch adds a worktree from an existing branch.
-func WorktreeAddFromBranch(branch, worktreePath string) error {
-	return Command("worktree", "add", worktreePath, branch)
+func WorktreeAddFromBranch(ctx context.Context, branch, worktreePath string) error {
+	return CommandCaptureContext(ctx, "worktree", "add", worktreePath, branch)
+}
+
+// SubmoduleUpdate initializes and checks out the submodules of the worktree at path, recursively,
+// streaming git's progress.
+func SubmoduleUpdate(ctx context.Context, path string) error {
+	return CommandContext(ctx, "-C", path, "submodule", "update", "--init", "--recursive")
 }
 
 // WorktreeRemove removes a worktree.
-func WorktreeRemove(worktreePath string, force bool) error {
+func WorktreeRemove(ctx context.Context, worktreePath string, force bool) error {
 	args := []string{"worktree", "remove", worktreePath}
 	if force {
 		args = append(args, "--force")
 	}
-	return Command(args...)
+	return CommandCaptureContext(ctx, args...)
+}
+
+// WorktreeMove moves a worktree to a new path.
+func WorktreeMove(ctx context.Context, oldPath, newPath string) error {
+	if err := RequireVersion(MinVersionMove, "moving worktrees"); err != nil {
+		return err
+	}
+	return CommandCaptureContext(ctx, "worktree", "move", oldPath, newPath)
+}
+
+// WorktreeLock locks a worktree so that it cannot be pruned, moved, or removed.
+func WorktreeLock(worktreePath, reason string) error {
+	if err := RequireVersion(MinVersionLock, "locking worktrees"); err != nil {
+		return err
+	}
+	args := []string{"worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	return CommandCapture(append(args, worktreePath)...)
+}
+
+// WorktreeUnlock unlocks a locked worktree.
+func WorktreeUnlock(worktreePath string) error {
+	if err := RequireVersion(MinVersionLock, "unlocking worktrees"); err != nil {
+		return err
+	}
+	return CommandCapture("worktree", "unlock", worktreePath)
+}
+
+// WorktreeRepair repairs the administrative links of worktrees after they or the main
+// worktree were moved. Without paths, all registered worktrees are repaired.
+func WorktreeRepair(paths ...string) error {
+	if err := RequireVersion(MinVersionRepair, "repairing worktrees"); err != nil {
+		return err
+	}
+	return CommandCapture(append([]string{"worktree", "repair"}, paths...)...)
+}
+
+// Fetch fetches refs from remote.
+func Fetch(ctx context.Context, remote string, refs ...string) error {
+	return FetchDepth(ctx, remote, 0, refs...)
+}
+
+// FetchDepth fetches refs from remote, limiting history to depth commits when depth is positive.
+func FetchDepth(ctx context.Context, remote string, depth int, refs ...string) error {
+	return fetch(ctx, remote, depth, false, refs)
 }
 
-// Fetch fetches refs from origin.
-func Fetch(refs ...string) error {
-	args := append([]string{"fetch", "origin"}, refs...)
-	return Command(args...)
+// FetchWithPrune fetches refs from remote and deletes remote-tracking branches of remote
+// that no longer exist on it. Without refs all branches of remote are fetched.
+func FetchWithPrune(ctx context.Context, remote string, refs ...string) error {
+	return fetch(ctx, remote, 0, true, refs)
+}
+
+func fetch(ctx context.Context, remote string, depth int, prune bool, refs []string) error {
+	args := []string{"fetch", remote}
+	if depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", depth))
+	}
+	if prune {
+		args = append(args, "--prune")
+	}
+	args = append(args, refs...)
+	return CommandContext(ctx, args...)
+}
+
+// IgnoredPaths returns the untracked paths under path that are ignored by .gitignore,
+// .git/info/exclude, or the global excludes file. Paths are relative to repoDir and
+// fully ignored directories are listed once with a trailing slash.
+func IgnoredPaths(repoDir, path string) ([]string, error) {
+	out, err := CommandOutputAt(repoDir, "ls-files", "--others", "--ignored", "--exclude-standard", "--directory", "-z", "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ignored files: %s", gitMessage(out))
+	}
+	var paths []string
+	for _, p := range strings.Split(out, "\x00") {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
+
+// StatusSummary returns the short status of the worktree at path, listing at most maxLines
+// changed files followed by a count of the remaining ones.
+func StatusSummary(path string, maxLines int) (string, error) {
+	out, err := CommandOutputAt(path, "status", "--short")
+	if err != nil {
+		return "", fmt.Errorf("failed to get status: %s", gitMessage(out))
+	}
+
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) > maxLines {
+		more := len(lines) - maxLines
+		lines = append(lines[:maxLines], fmt.Sprintf("... and %d more", more))
+	}
+	return strings.Join(lines, "\n"), nil
 }
 
 // HasUncommittedChanges checks if a worktree has uncommitted changes.
 func HasUncommittedChanges(worktreePath string) bool {
 	// Check for staged or unstaged changes
-	cmd := exec.Command("git", "status", "--porcelain")
+	cmd := newCommand(context.Background(), "status", "--porcelain")
 	cmd.Dir = worktreePath
 	out, err := cmd.Output()
 	if err != nil {
@@ -81,38 +406,62 @@ func HasUncommittedChanges(worktreePath string) bool {
 
 // WorktreeInfo represents information about a worktree.
 type WorktreeInfo struct {
-	Path   string
-	Branch string
+	Path       string
+	Branch     string
+	Head       string
+	Bare       bool
+	Detached   bool
+	Locked     bool
+	LockReason string
 }
 
-// GetWorktreeInfo returns worktree info (path and branch) for all worktrees.
+// GetWorktreeInfo returns worktree info for all worktrees.
 func GetWorktreeInfo() ([]WorktreeInfo, error) {
+	return WorktreeListDetailed()
+}
+
+// WorktreeListDetailed parses 'git worktree list --porcelain' into one entry per worktree.
+// Git always lists the main worktree first.
+func WorktreeListDetailed() ([]WorktreeInfo, error) {
 	out, err := CommandOutput("worktree", "list", "--porcelain")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
+	return parseWorktreeList(out), nil
+}
 
+// parseWorktreeList parses porcelain output, where each worktree is a block of lines
+// starting with "worktree <path>".
+func parseWorktreeList(out string) []WorktreeInfo {
 	var worktrees []WorktreeInfo
-	var current WorktreeInfo
-	lines := strings.Split(out, "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, "worktree ") {
-			if current.Path != "" {
-				worktrees = append(worktrees, current)
-			}
-			current = WorktreeInfo{
-				Path: strings.TrimPrefix(line, "worktree "),
-			}
-		} else if strings.HasPrefix(line, "branch ") {
-			branch := strings.TrimPrefix(line, "branch ")
+	var current *WorktreeInfo
+	for _, line := range strings.Split(out, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		if key == "worktree" {
+			worktrees = append(worktrees, WorktreeInfo{Path: value})
+			current = &worktrees[len(worktrees)-1]
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		switch key {
+		case "HEAD":
+			current.Head = value
+		case "branch":
 			// Strip "refs/heads/" prefix if present
-			current.Branch = strings.TrimPrefix(branch, "refs/heads/")
+			current.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "bare":
+			current.Bare = true
+		case "detached":
+			current.Detached = true
+		case "locked":
+			current.Locked = true
+			current.LockReason = value
 		}
 	}
-	if current.Path != "" {
-		worktrees = append(worktrees, current)
-	}
-	return worktrees, nil
+	return worktrees
 }
 
 // WorktreeIsRegistered checks if a worktree path is registered in git.
@@ -143,24 +492,138 @@ func GetWorktreeBranch(worktreePath string) (string, error) {
 	return "", nil
 }
 
+// GetMainWorktreePath returns the path of the main worktree.
+// Git always lists the main worktree first.
+func GetMainWorktreePath() (string, error) {
+	worktrees, err := GetWorktreeInfo()
+	if err != nil {
+		return "", err
+	}
+	if len(worktrees) == 0 {
+		return "", fmt.Errorf("no worktrees found")
+	}
+	return worktrees[0].Path, nil
+}
+
+// GetMainWorktreeDir returns the directory holding the files of the main worktree.
+// GetMainWorktreePath returns the path git lists, which git derives from the git directory; that
+// is wrong when the work tree lives elsewhere because of core.worktree or --separate-git-dir.
+// This uses core.worktree if set, or the top level when run from the main worktree itself.
+func GetMainWorktreeDir() (string, error) {
+	listed, err := GetMainWorktreePath()
+	if err != nil {
+		return "", err
+	}
+	commonDir, err := GetGitCommonDir(".")
+	if err != nil || IsBareRepository(commonDir) {
+		return listed, nil
+	}
+
+	if out, err := CommandOutput("config", "--file", filepath.Join(commonDir, "config"), "--get", "core.worktree"); err == nil {
+		if dir := strings.TrimSpace(out); dir != "" {
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(commonDir, dir)
+			}
+			return filepath.Clean(dir), nil
+		}
+	}
+
+	// Only the main worktree uses the common directory as its git directory.
+	if gitDir, err := GetGitDir("."); err == nil && gitDir == commonDir {
+		if out, err := CommandStdout("rev-parse", "--show-toplevel"); err == nil && strings.TrimSpace(out) != "" {
+			return strings.TrimSpace(out), nil
+		}
+	}
+	return listed, nil
+}
+
 // WorktreePrune prunes stale worktree records.
-func WorktreePrune() error {
-	return CommandSilent("worktree", "prune")
+func WorktreePrune(ctx context.Context) error {
+	return CommandSilentContext(ctx, "worktree", "prune")
 }
 
 // IsGitRepository checks if a directory is a git repository.
 func IsGitRepository(path string) bool {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
+	cmd := newCommand(context.Background(), "rev-parse", "--git-dir")
 	cmd.Dir = path
 	err := cmd.Run()
 	return err == nil
 }
 
-// GetRepoName returns the repository name from the current working directory.
+// GetGitDir returns the git directory for the repository at path.
+func GetGitDir(path string) (string, error) {
+	out, err := CommandOutputAt(path, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to get git directory: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// GetGitCommonDir returns the common git directory shared by all worktrees of the repository at path.
+func GetGitCommonDir(path string) (string, error) {
+	out, err := CommandOutputAt(path, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to get git common directory: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// IsBareRepository checks if the repository at path is a bare repository.
+func IsBareRepository(path string) bool {
+	out, err := CommandOutputAt(path, "rev-parse", "--is-bare-repository")
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(out) == "true"
+}
+
+// BareDirName is the directory holding the bare repository in the "one bare repo + many worktrees" layout.
+const BareDirName = ".bare"
+
+// CloneBare clones url as a bare repository into dir. Progress is shown when stderr is a
+// terminal; git would not show it by itself, since its stderr is also captured for errors.
+func CloneBare(ctx context.Context, url, dir string) error {
+	args := []string{"clone", "--bare"}
+	if term.IsTerminal(os.Stderr) {
+		args = append(args, "--progress")
+	}
+	return CommandStreamContext(ctx, append(args, url, dir)...)
+}
+
+// ConfigRemote sets the fetch refspec of remote in gitDir so that fetches create
+// remote-tracking branches, which bare clones do not do by default.
+func ConfigRemote(gitDir, remote string) error {
+	refspec := fmt.Sprintf("+refs/heads/*:refs/remotes/%s/*", remote)
+	return CommandCapture("--git-dir", gitDir, "config", "remote."+remote+".fetch", refspec)
+}
+
+// RemoteURL returns the URL of remote in gitDir.
+func RemoteURL(gitDir, remote string) (string, error) {
+	out, err := CommandStdout("--git-dir", gitDir, "config", "--get", "remote."+remote+".url")
+	return strings.TrimSpace(out), err
+}
+
+// BareLayoutRoot returns the directory containing the .bare repository when the
+// current repository uses the bare layout created by CloneBare.
+func BareLayoutRoot() (string, bool) {
+	commonDir, err := GetGitCommonDir(".")
+	if err != nil || filepath.Base(commonDir) != BareDirName || !IsBareRepository(commonDir) {
+		return "", false
+	}
+	return filepath.Dir(commonDir), true
+}
+
+// GetRepoName returns the name of the repository in the current working directory: the
+// directory name of its main worktree, so that it is the same in linked worktrees and
+// subdirectories, and wherever core.worktree points. A ".git" suffix of bare repositories is
+// dropped. In the bare layout it is the name of the directory containing .bare.
 func GetRepoName() (string, error) {
-	cwd, err := os.Getwd()
+	if root, ok := BareLayoutRoot(); ok {
+		return filepath.Base(root), nil
+	}
+	mainPath, err := GetMainWorktreeDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get current directory: %w", err)
+		return "", fmt.Errorf("failed to determine repository name: %w", err)
 	}
-	return filepath.Base(cwd), nil
+	return strings.TrimSuffix(filepath.Base(mainPath), ".git"), nil
 }
@@ -0,0 +1,141 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// gitRun runs git with args in dir and fails the test if it fails.
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_GLOBAL="+filepath.Join(t.TempDir(), "gitconfig"), "GIT_CONFIG_NOSYSTEM=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+// testRepos creates a normal repository with a linked worktree, and a bare repository.
+func testRepos(t *testing.T) (repo, linked, bare string) {
+	t.Helper()
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo = filepath.Join(root, "repo")
+	linked = filepath.Join(root, "linked")
+	bare = filepath.Join(root, "bare.git")
+
+	gitRun(t, root, "init", "-q", "-b", "main", repo)
+	gitRun(t, repo, "commit", "-q", "--allow-empty", "-m", "init")
+	if err := os.Mkdir(filepath.Join(repo, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, repo, "worktree", "add", "-q", "-b", "feature", linked)
+	gitRun(t, root, "init", "-q", "--bare", bare)
+	return repo, linked, bare
+}
+
+func TestGitDirs(t *testing.T) {
+	repo, linked, bare := testRepos(t)
+	tests := []struct {
+		name      string
+		path      string
+		gitDir    string
+		commonDir string
+		bare      bool
+	}{
+		{"normal repository", repo, filepath.Join(repo, ".git"), filepath.Join(repo, ".git"), false},
+		{"subdirectory", filepath.Join(repo, "sub"), filepath.Join(repo, ".git"), filepath.Join(repo, ".git"), false},
+		{"linked worktree", linked, filepath.Join(repo, ".git", "worktrees", "linked"), filepath.Join(repo, ".git"), false},
+		{"bare repository", bare, bare, bare, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := GetGitDir(tt.path); err != nil || got != tt.gitDir {
+				t.Errorf("GetGitDir() = %q, %v; want %q", got, err, tt.gitDir)
+			}
+			if got, err := GetGitCommonDir(tt.path); err != nil || got != tt.commonDir {
+				t.Errorf("GetGitCommonDir() = %q, %v; want %q", got, err, tt.commonDir)
+			}
+			if got := IsBareRepository(tt.path); got != tt.bare {
+				t.Errorf("IsBareRepository() = %v, want %v", got, tt.bare)
+			}
+		})
+	}
+}
+
+func TestGitDirsOutsideRepository(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+	if got, err := GetGitDir(dir); err == nil {
+		t.Errorf("GetGitDir() = %q, want an error", got)
+	}
+	if got, err := GetGitCommonDir(dir); err == nil {
+		t.Errorf("GetGitCommonDir() = %q, want an error", got)
+	}
+	if IsBareRepository(dir) {
+		t.Error("IsBareRepository() = true, want false")
+	}
+}
+
+func TestCommandStdout(t *testing.T) {
+	repo, _, _ := testRepos(t)
+	t.Chdir(repo)
+	// GIT_TRACE makes git write to stderr even when it succeeds.
+	t.Setenv("GIT_TRACE", "1")
+
+	out, err := CommandStdout("rev-parse", "--show-toplevel")
+	if err != nil {
+		t.Fatalf("CommandStdout() error = %v", err)
+	}
+	if got := strings.TrimSpace(out); got != repo {
+		t.Errorf("CommandStdout() = %q, want %q", got, repo)
+	}
+
+	_, err = CommandStdout("rev-parse", "--verify", "missing-ref")
+	var gitErr *Error
+	if !errors.As(err, &gitErr) || gitErr.Stderr == "" {
+		t.Errorf("CommandStdout() error = %#v, want an *Error with git's message", err)
+	}
+}
+
+func TestGetMainWorktreeDir(t *testing.T) {
+	repo, linked, _ := testRepos(t)
+	// A repository whose work tree is set with a relative core.worktree, as git init --separate-git-dir does.
+	root := filepath.Dir(repo)
+	separate, gitDir := filepath.Join(root, "separate"), filepath.Join(root, "separate.git")
+	gitRun(t, root, "init", "-q", "-b", "main", "--separate-git-dir", gitDir, separate)
+	gitRun(t, separate, "commit", "-q", "--allow-empty", "-m", "init")
+	gitRun(t, gitDir, "config", "core.worktree", "../separate")
+
+	tests := []struct {
+		name string
+		dir  string
+		want string
+	}{
+		{"main worktree", repo, repo},
+		{"subdirectory", filepath.Join(repo, "sub"), repo},
+		{"linked worktree", linked, repo},
+		{"relative core.worktree", separate, separate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(tt.dir)
+			if got, err := GetMainWorktreeDir(); err != nil || got != tt.want {
+				t.Errorf("GetMainWorktreeDir() = %q, %v; want %q", got, err, tt.want)
+			}
+			if got, err := GetRepoName(); err != nil || got != filepath.Base(tt.want) {
+				t.Errorf("GetRepoName() = %q, %v; want %q", got, err, filepath.Base(tt.want))
+			}
+		})
+	}
+}
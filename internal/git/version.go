@@ -0,0 +1,85 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// VersionNumber is a dotted version number such as 2.43.0.
+type VersionNumber struct {
+	Major, Minor, Patch int
+}
+
+func (v VersionNumber) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether v is the same as or newer than other.
+func (v VersionNumber) AtLeast(other VersionNumber) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+	return v.Patch >= other.Patch
+}
+
+// Minimum git versions. MinVersion is the oldest git every command works with,
+// the others are needed by individual worktree subcommands.
+var (
+	MinVersion           = VersionNumber{2, 31, 0}
+	MinVersionLock       = VersionNumber{2, 10, 0}
+	MinVersionMove       = VersionNumber{2, 17, 0}
+	MinVersionSparseCone = VersionNumber{2, 27, 0}
+	MinVersionRepair     = VersionNumber{2, 29, 0}
+)
+
+var versionPattern = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// The git version is cached since it cannot change during a run.
+var (
+	versionOnce      sync.Once
+	cachedVersion    VersionNumber
+	cachedVersionErr error
+)
+
+// ParseVersion parses the first version number in s, such as "git version 2.43.0.windows.1".
+func ParseVersion(s string) (VersionNumber, error) {
+	m := versionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return VersionNumber{}, fmt.Errorf("no version number in %q", s)
+	}
+	var v VersionNumber
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+	}
+	return v, nil
+}
+
+// Version returns the version of the git binary. It is queried once per run.
+func Version() (VersionNumber, error) {
+	versionOnce.Do(func() {
+		out, err := CommandOutput("--version")
+		if err != nil {
+			cachedVersionErr = fmt.Errorf("failed to get git version: %w", err)
+			return
+		}
+		cachedVersion, cachedVersionErr = ParseVersion(out)
+	})
+	return cachedVersion, cachedVersionErr
+}
+
+// RequireVersion returns an error if git is older than minVersion, naming the feature that needs it.
+// If the version cannot be determined, git is left to report any problem itself.
+func RequireVersion(minVersion VersionNumber, feature string) error {
+	v, err := Version()
+	if err != nil || v.AtLeast(minVersion) {
+		return nil
+	}
+	return fmt.Errorf("%s requires git %s or newer, but git %s is installed", feature, minVersion, v)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 )
 
 type (
@@ -53,27 +54,82 @@ func colorPrint(code string) PrintFunc {
 	}
 }
 
-// Logger is a wrapper that prints stuff to STDOUT or STDERR,
-// with optional color and verbosity.
+// Level is the severity of a log message. A Logger prints messages at or above its level.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+// String returns the name of the level as accepted by ParseLevel.
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// ParseLevel parses a level name: debug, info, warn, or error.
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("invalid log level %q: must be one of debug, info, warn, error", name)
+}
+
+// Logger is a wrapper that prints stuff to STDOUT or STDERR, with optional color.
+// Log messages (Debugf, Infof, Warnf, Errorf) go to STDERR and are filtered by Level.
+// Human-readable results (Outf) go to STDOUT and are printed at info level or below;
+// Plainf is for machine-readable results such as paths and is always printed.
 type Logger struct {
-	Stdout  io.Writer
-	Stderr  io.Writer
-	Verbose bool
-	Color   bool
+	Stdout io.Writer
+	Stderr io.Writer
+	Level  Level
+	Color  bool
 }
 
 // NewLogger creates a new Logger instance.
-func NewLogger(verbose, useColor bool) *Logger {
+func NewLogger(level Level, useColor bool) *Logger {
 	return &Logger{
-		Stdout:  os.Stdout,
-		Stderr:  os.Stderr,
-		Verbose: verbose,
-		Color:   useColor,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+		Level:  level,
+		Color:  useColor,
 	}
 }
 
-// Outf prints stuff to STDOUT.
+// Enabled reports whether messages at level are printed.
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.Level
+}
+
+// Quiet reports whether informational output is suppressed, leaving only results and problems.
+func (l *Logger) Quiet() bool {
+	return !l.Enabled(LevelInfo)
+}
+
+// DebugWriter returns STDERR at debug level and nil otherwise, for tracing commands.
+func (l *Logger) DebugWriter() io.Writer {
+	if !l.Enabled(LevelDebug) {
+		return nil
+	}
+	return l.Stderr
+}
+
+// Outf prints results to STDOUT unless informational output is suppressed.
 func (l *Logger) Outf(c Color, s string, args ...any) {
+	if l.Quiet() {
+		return
+	}
 	l.FOutf(l.Stdout, c, s, args...)
 }
 
@@ -89,45 +145,36 @@ func (l *Logger) FOutf(w io.Writer, c Color, s string, args ...any) {
 	print(w, s, args...)
 }
 
-// VerboseOutf prints stuff to STDOUT if verbose mode is enabled.
-func (l *Logger) VerboseOutf(c Color, s string, args ...any) {
-	if l.Verbose {
-		l.Outf(c, s, args...)
-	}
-}
-
-// Errf prints stuff to STDERR.
+// Errf prints stuff to STDERR regardless of the level.
 func (l *Logger) Errf(c Color, s string, args ...any) {
-	if len(args) == 0 {
-		s, args = "%s", []any{s}
-	}
-	if !l.Color {
-		c = None
-	}
-	print := c()
-	print(l.Stderr, s, args...)
+	l.FOutf(l.Stderr, c, s, args...)
 }
 
-// VerboseErrf prints stuff to STDERR if verbose mode is enabled.
-func (l *Logger) VerboseErrf(c Color, s string, args ...any) {
-	if l.Verbose {
+// logf prints a message to STDERR if level is enabled.
+func (l *Logger) logf(level Level, c Color, s string, args ...any) {
+	if l.Enabled(level) {
 		l.Errf(c, s, args...)
 	}
 }
 
-// Warnf prints a warning message to STDERR.
+// Debugf prints a diagnostic message, such as a command being run, to STDERR.
+func (l *Logger) Debugf(s string, args ...any) {
+	l.logf(LevelDebug, Default, s, args...)
+}
+
+// Infof prints a progress message to STDERR.
+func (l *Logger) Infof(s string, args ...any) {
+	l.logf(LevelInfo, Cyan, s, args...)
+}
+
+// Warnf prints a warning about a recoverable problem to STDERR.
 func (l *Logger) Warnf(s string, args ...any) {
-	l.Errf(Yellow, s, args...)
+	l.logf(LevelWarn, Yellow, s, args...)
 }
 
 // Errorf prints an error message to STDERR.
 func (l *Logger) Errorf(s string, args ...any) {
-	l.Errf(Red, s, args...)
-}
-
-// Infof prints an informational message to STDOUT.
-func (l *Logger) Infof(s string, args ...any) {
-	l.Outf(Cyan, s, args...)
+	l.logf(LevelError, Red, s, args...)
 }
 
 // Plainf prints a plain message to STDOUT.
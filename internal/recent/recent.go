@@ -0,0 +1,84 @@
+package recent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the name of the history file in the config directory.
+const FileName = "recent.json"
+
+// MaxEntries is the number of worktrees the history remembers.
+const MaxEntries = 20
+
+// Entry is a worktree that was recently created, opened, or switched to.
+type Entry struct {
+	Path   string    `json:"path"`
+	UsedAt time.Time `json:"usedAt"`
+}
+
+// Load reads the history kept in dir, most recently used first.
+// A missing history file is an empty history.
+func Load(dir string) ([]Entry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid %s in %s: %w", FileName, dir, err)
+	}
+	return entries, nil
+}
+
+// Record moves path to the front of the history kept in dir, dropping the oldest
+// entries beyond MaxEntries.
+func Record(dir, path string) error {
+	entries, err := Load(dir)
+	if err != nil {
+		// A corrupt history is not worth failing over; start a new one.
+		entries = nil
+	}
+
+	updated := []Entry{{Path: path, UsedAt: time.Now().UTC()}}
+	for _, e := range entries {
+		if e.Path != path && len(updated) < MaxEntries {
+			updated = append(updated, e)
+		}
+	}
+	return save(dir, updated)
+}
+
+// save writes entries to the history file in dir, replacing it atomically so that
+// concurrent readers never see a partial file.
+func save(dir string, entries []Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, FileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, FileName))
+}
@@ -0,0 +1,80 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// Shell is an interactive shell that commands are printed for.
+type Shell string
+
+const (
+	Bash       Shell = "bash"
+	Zsh        Shell = "zsh"
+	Fish       Shell = "fish"
+	PowerShell Shell = "powershell"
+)
+
+// Names lists the shells accepted by Parse.
+var Names = []Shell{Bash, Zsh, Fish, PowerShell}
+
+// Parse returns the shell called name. pwsh is accepted for PowerShell.
+func Parse(name string) (Shell, error) {
+	name = strings.ToLower(name)
+	if name == "pwsh" {
+		return PowerShell, nil
+	}
+	for _, sh := range Names {
+		if name == string(sh) {
+			return sh, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported shell %q: must be one of bash, zsh, fish, powershell", name)
+}
+
+// Detect guesses the user's shell from $SHELL. Unknown shells are treated as bash, since
+// POSIX shells share its quoting, except on Windows where PowerShell is assumed.
+func Detect() Shell {
+	name := strings.TrimSuffix(filepath.Base(os.Getenv("SHELL")), ".exe")
+	if sh, err := Parse(name); err == nil {
+		return sh
+	}
+	if runtime.GOOS == "windows" {
+		return PowerShell
+	}
+	return Bash
+}
+
+// safeWord matches strings that need no quoting in any supported shell. A leading "=" is
+// expanded to a command path by zsh and a leading "@" splats a variable in PowerShell, and ","
+// makes an array in PowerShell.
+var safeWord = regexp.MustCompile(`^[a-zA-Z0-9_./:%+-][a-zA-Z0-9_./:@%+=-]*$`)
+
+// powerShellQuotes are the characters PowerShell accepts as single quotes, including typographic ones.
+var powerShellQuotes = strings.NewReplacer("'", "''", "\u2018", "\u2018\u2018", "\u2019", "\u2019\u2019", "\u201a", "\u201a\u201a", "\u201b", "\u201b\u201b")
+
+// Quote quotes s as a single word for sh.
+func Quote(sh Shell, s string) string {
+	if safeWord.MatchString(s) {
+		return s
+	}
+	switch sh {
+	case Fish:
+		// Inside single quotes fish only treats \' and \\ specially.
+		s = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+		return "'" + s + "'"
+	case PowerShell:
+		return "'" + powerShellQuotes.Replace(s) + "'"
+	default:
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	}
+}
+
+// Cd returns the command that changes the directory of sh to path.
+func Cd(sh Shell, path string) string {
+	return "cd " + Quote(sh, path)
+}
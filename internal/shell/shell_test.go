@@ -0,0 +1,107 @@
+package shell
+
+import (
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		in    string
+		bash  string
+		fish  string
+		pwsh  string
+		quote bool
+	}{
+		{"/home/me/wt/pr_1", "/home/me/wt/pr_1", "/home/me/wt/pr_1", "/home/me/wt/pr_1", false},
+		{"", "''", "''", "''", true},
+		{"/my worktrees/pr 1", "'/my worktrees/pr 1'", "'/my worktrees/pr 1'", "'/my worktrees/pr 1'", true},
+		{"it's", `'it'\''s'`, `'it\'s'`, "'it''s'", true},
+		{`say "hi"`, `'say "hi"'`, `'say "hi"'`, `'say "hi"'`, true},
+		{"$HOME/x", "'$HOME/x'", "'$HOME/x'", "'$HOME/x'", true},
+		{"$(rm -rf ~)", "'$(rm -rf ~)'", "'$(rm -rf ~)'", "'$(rm -rf ~)'", true},
+		{"`id`", "'`id`'", "'`id`'", "'`id`'", true},
+		{`C:\Users\me`, `'C:\Users\me'`, `'C:\\Users\\me'`, `'C:\Users\me'`, true},
+		{"it’s", "'it’s'", "'it’s'", "'it’’s'", true},
+		{"a,b", "'a,b'", "'a,b'", "'a,b'", true},
+		{"@args", "'@args'", "'@args'", "'@args'", true},
+		{"=ls", "'=ls'", "'=ls'", "'=ls'", true},
+		{"a=b@c", "a=b@c", "a=b@c", "a=b@c", false},
+		{"*?[x]", "'*?[x]'", "'*?[x]'", "'*?[x]'", true},
+		{"line\nbreak", "'line\nbreak'", "'line\nbreak'", "'line\nbreak'", true},
+	}
+	for _, tt := range tests {
+		for sh, want := range map[Shell]string{Bash: tt.bash, Zsh: tt.bash, Fish: tt.fish, PowerShell: tt.pwsh} {
+			if got := Quote(sh, tt.in); got != want {
+				t.Errorf("Quote(%s, %q) = %s, want %s", sh, tt.in, got, want)
+			}
+		}
+	}
+}
+
+// TestQuoteRoundTrip checks that the shells that are installed read quoted words back unchanged.
+func TestQuoteRoundTrip(t *testing.T) {
+	words := []string{"plain", "", "two words", "it's", `say "hi"`, "$HOME", "$(id)", "`id`", `back\slash`, "it’s", "a,b", "@args", "=ls", "*", "tab\there"}
+	shells := []struct {
+		sh   Shell
+		argv []string
+	}{
+		{Bash, []string{"bash", "-c"}},
+		{Zsh, []string{"zsh", "-f", "-c"}},
+		{Fish, []string{"fish", "--no-config", "-c"}},
+		{PowerShell, []string{"pwsh", "-NoProfile", "-NonInteractive", "-Command"}},
+	}
+	for _, tt := range shells {
+		t.Run(string(tt.sh), func(t *testing.T) {
+			if _, err := exec.LookPath(tt.argv[0]); err != nil {
+				t.Skipf("%s is not installed", tt.argv[0])
+			}
+			for _, word := range words {
+				script := "printf '%s' " + Quote(tt.sh, word)
+				if tt.sh == PowerShell {
+					script = "[Console]::Out.Write(" + Quote(tt.sh, word) + ")"
+				}
+				args := append(slices.Clone(tt.argv), script)
+				out, err := exec.Command(args[0], args[1:]...).Output()
+				if err != nil {
+					t.Errorf("%s: %v", script, err)
+					continue
+				}
+				if string(out) != word {
+					t.Errorf("%s printed %q, want %q", script, out, word)
+				}
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Shell
+		wantErr bool
+	}{
+		{"bash", Bash, false},
+		{"ZSH", Zsh, false},
+		{"fish", Fish, false},
+		{"powershell", PowerShell, false},
+		{"pwsh", PowerShell, false},
+		{"tcsh", "", true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.name)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) = %q, %v; want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCd(t *testing.T) {
+	if got, want := Cd(Bash, "/tmp/my wt"), "cd '/tmp/my wt'"; got != want {
+		t.Errorf("Cd() = %q, want %q", got, want)
+	}
+	if got, want := Cd(PowerShell, `C:\wt\it's`), `cd 'C:\wt\it''s'`; got != want {
+		t.Errorf("Cd() = %q, want %q", got, want)
+	}
+}
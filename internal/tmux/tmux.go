@@ -0,0 +1,25 @@
+package tmux
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// InSession reports whether the current process runs inside a tmux session.
+func InSession() bool {
+	return os.Getenv("TMUX") != ""
+}
+
+// NewWindow opens a new tmux window named name with dir as its working directory.
+func NewWindow(name, dir string) error {
+	out, err := exec.Command("tmux", "new-window", "-c", dir, "-n", name).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("tmux new-window failed: %s", msg)
+		}
+		return fmt.Errorf("tmux new-window failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package worktree
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ffalor/gh-wt/internal/git"
+)
+
+// CopyFiles copies files matching the glob patterns from srcDir into dstDir.
+// Patterns are relative to srcDir and the relative layout is preserved.
+// Patterns without matches are skipped, and directories are copied recursively.
+// With respectGitignore, ignored paths inside copied directories are skipped unless
+// the matched directory is ignored itself.
+func CopyFiles(srcDir, dstDir string, patterns []string, respectGitignore bool) error {
+	for _, pattern := range patterns {
+		if isAbs(pattern) {
+			return fmt.Errorf("invalid copy pattern %q: must be relative to the repository", pattern)
+		}
+		matches, err := filepath.Glob(filepath.Join(srcDir, pattern))
+		if err != nil {
+			return fmt.Errorf("invalid copy pattern %q: %w", pattern, err)
+		}
+
+		for _, src := range matches {
+			rel, err := filepath.Rel(srcDir, src)
+			if err != nil {
+				return err
+			}
+			if escapes(rel) {
+				return fmt.Errorf("invalid copy pattern %q: %s is outside the repository", pattern, rel)
+			}
+			var skip map[string]bool
+			if respectGitignore {
+				if skip, err = ignoredBelow(srcDir, rel); err != nil {
+					return err
+				}
+			}
+			if err := copyPath(src, filepath.Join(dstDir, rel), skip); err != nil {
+				return fmt.Errorf("failed to copy %s: %w", rel, err)
+			}
+		}
+	}
+	return nil
+}
+
+// isAbs reports whether path is absolute, or has a drive letter on Windows.
+func isAbs(path string) bool {
+	return filepath.IsAbs(path) || filepath.VolumeName(path) != "" || strings.HasPrefix(filepath.ToSlash(path), "/")
+}
+
+// escapes reports whether the relative path rel leads out of the directory it is relative to.
+func escapes(rel string) bool {
+	rel = filepath.Clean(rel)
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ignoredBelow returns the ignored paths inside the directory rel of srcDir, relative to rel.
+// Nothing is skipped for files, or for directories that are ignored as a whole, since they were requested explicitly.
+func ignoredBelow(srcDir, rel string) (map[string]bool, error) {
+	if fi, err := os.Stat(filepath.Join(srcDir, rel)); err != nil || !fi.IsDir() {
+		return nil, nil
+	}
+
+	ignored, err := git.IgnoredPaths(srcDir, rel)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := filepath.ToSlash(rel) + "/"
+	skip := make(map[string]bool, len(ignored))
+	for _, path := range ignored {
+		if path == prefix {
+			return nil, nil
+		}
+		if sub, ok := strings.CutPrefix(strings.TrimSuffix(path, "/"), prefix); ok {
+			skip[filepath.FromSlash(sub)] = true
+		}
+	}
+	return skip, nil
+}
+
+// copyPath copies a file, symlink, or directory tree from src to dst.
+// Paths relative to src in skip are not copied.
+func copyPath(src, dst string, skip map[string]bool) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if skip[rel] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			_ = os.Remove(target)
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return nil
+		}
+	})
+}
+
+// copyFile copies a regular file, creating parent directories as needed.
+func copyFile(src, dst string, perm fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
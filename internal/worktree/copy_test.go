@@ -0,0 +1,125 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCopyFiles(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(src, ".env"), "SECRET=1")
+	writeFile(t, filepath.Join(src, "config", "local", "settings.json"), "{}")
+	writeFile(t, filepath.Join(src, "web", "sub", "..", ".env.local"), "X=1")
+
+	patterns := []string{".env", "config", "missing.txt", "web/sub/../.env*"}
+	if err := CopyFiles(src, dst, patterns, false); err != nil {
+		t.Fatalf("CopyFiles() error = %v", err)
+	}
+
+	for _, path := range []string{".env", "config/local/settings.json", "web/.env.local"} {
+		if _, err := os.Stat(filepath.Join(dst, path)); err != nil {
+			t.Errorf("%s was not copied: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dst, "missing.txt")); !os.IsNotExist(err) {
+		t.Errorf("missing.txt exists in the destination: %v", err)
+	}
+}
+
+func TestCopyFilesRespectGitignore(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), ".gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	if out, err := exec.Command("git", "init", "-q", src).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+
+	writeFile(t, filepath.Join(src, ".gitignore"), "*.log\nbuild/\n")
+	// A nested .gitignore adds rules for its directory and re-includes keep.log.
+	writeFile(t, filepath.Join(src, "config", ".gitignore"), "cache/\n!keep.log\n")
+	for _, path := range []string{
+		"config/app.yaml",
+		"config/debug.log",
+		"config/keep.log",
+		"config/cache/data",
+		"config/nested/deep.log",
+		"build/out.log",
+	} {
+		writeFile(t, filepath.Join(src, path), path)
+	}
+
+	if err := CopyFiles(src, dst, []string{"config", "build"}, true); err != nil {
+		t.Fatalf("CopyFiles() error = %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		copied bool
+	}{
+		{"config/app.yaml", true},
+		{"config/.gitignore", true},
+		{"config/keep.log", true},
+		{"config/debug.log", false},
+		{"config/cache/data", false},
+		{"config/nested/deep.log", false},
+		// A directory that is ignored as a whole was asked for explicitly, so all of it is copied.
+		{"build/out.log", true},
+	}
+	for _, tt := range tests {
+		_, err := os.Stat(filepath.Join(dst, tt.path))
+		if copied := err == nil; copied != tt.copied {
+			t.Errorf("%s copied = %v, want %v", tt.path, copied, tt.copied)
+		}
+	}
+}
+
+func TestCopyFilesOutsideRepository(t *testing.T) {
+	root := t.TempDir()
+	src, dst := filepath.Join(root, "repo"), filepath.Join(root, "worktrees", "wt")
+	writeFile(t, filepath.Join(src, "README.md"), "readme")
+	writeFile(t, filepath.Join(root, "secret"), "secret")
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pattern := range []string{
+		"../secret",
+		"../*",
+		"config/../../secret",
+		filepath.Join(root, "secret"),
+		"/secret",
+	} {
+		if err := CopyFiles(src, dst, []string{pattern}, false); err == nil {
+			t.Errorf("CopyFiles(%q) succeeded, want an error", pattern)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "worktrees", "secret")); !os.IsNotExist(err) {
+		t.Errorf("a file was written outside the worktree: %v", err)
+	}
+}
+
+func TestLinkDirsOutsideRepository(t *testing.T) {
+	root := t.TempDir()
+	src, dst := filepath.Join(root, "repo"), filepath.Join(root, "wt")
+	if err := os.MkdirAll(filepath.Join(root, "outside"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range []string{"../outside", filepath.Join(root, "outside")} {
+		if _, err := LinkDirs(src, dst, []string{dir}); err == nil {
+			t.Errorf("LinkDirs(%q) succeeded, want an error", dir)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package worktree
+
+import "strings"
+
+// Match scores returned by MatchScore, from worst to best.
+const (
+	NoMatch = iota
+	SubsequenceMatch
+	SubstringMatch
+	PrefixMatch
+	ExactMatch
+)
+
+// MatchScore reports how well query matches name, ignoring case. Higher scores are better matches:
+// an exact name beats a prefix, a prefix beats a substring, and a substring beats the characters of
+// query appearing in order anywhere in name.
+func MatchScore(query, name string) int {
+	query, name = strings.ToLower(query), strings.ToLower(name)
+	switch {
+	case query == "":
+		return NoMatch
+	case query == name:
+		return ExactMatch
+	case strings.HasPrefix(name, query):
+		return PrefixMatch
+	case strings.Contains(name, query):
+		return SubstringMatch
+	case isSubsequence(query, name):
+		return SubsequenceMatch
+	default:
+		return NoMatch
+	}
+}
+
+// BestMatches returns the indexes of the names that match query with the highest score, in order.
+// It returns nil if no name matches.
+func BestMatches(query string, names []string) []int {
+	best := NoMatch
+	var matches []int
+	for i, name := range names {
+		score := MatchScore(query, name)
+		if score == NoMatch || score < best {
+			continue
+		}
+		if score > best {
+			best, matches = score, nil
+		}
+		matches = append(matches, i)
+	}
+	return matches
+}
+
+// isSubsequence reports whether the runes of query appear in s in the same order.
+func isSubsequence(query, s string) bool {
+	q := []rune(query)
+	for _, r := range s {
+		if len(q) == 0 {
+			break
+		}
+		if r == q[0] {
+			q = q[1:]
+		}
+	}
+	return len(q) == 0
+}
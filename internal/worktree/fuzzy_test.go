@@ -0,0 +1,59 @@
+package worktree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMatchScore(t *testing.T) {
+	tests := []struct {
+		query string
+		name  string
+		want  int
+	}{
+		{"login", "login", ExactMatch},
+		{"LOGIN", "Login", ExactMatch},
+		{"log", "login", PrefixMatch},
+		{"gin", "login", SubstringMatch},
+		{"lgn", "login", SubsequenceMatch},
+		{"ülö", "über-lösung", SubsequenceMatch},
+		{"nil", "login", NoMatch},
+		{"logins", "login", NoMatch},
+		{"", "login", NoMatch},
+	}
+	for _, tt := range tests {
+		if got := MatchScore(tt.query, tt.name); got != tt.want {
+			t.Errorf("MatchScore(%q, %q) = %d, want %d", tt.query, tt.name, got, tt.want)
+		}
+	}
+
+	if !(ExactMatch > PrefixMatch && PrefixMatch > SubstringMatch && SubstringMatch > SubsequenceMatch && SubsequenceMatch > NoMatch) {
+		t.Error("match scores are not ordered exact > prefix > substring > subsequence > none")
+	}
+}
+
+func TestBestMatches(t *testing.T) {
+	tests := []struct {
+		query string
+		names []string
+		want  []int
+	}{
+		// Better kinds of match win whatever their position.
+		{"login", []string{"f-l-o-g-i-n", "my-login", "login-page", "login"}, []int{3}},
+		{"login", []string{"f-l-o-g-i-n", "my-login", "login-page"}, []int{2}},
+		{"login", []string{"f-l-o-g-i-n", "my-login"}, []int{1}},
+		{"login", []string{"f-l-o-g-i-n", "other"}, []int{0}},
+		// Ties keep every name with the best score, in their original order.
+		{"feat", []string{"feature-b", "bugfix", "feature-a"}, []int{0, 2}},
+		{"fix", []string{"hotfix", "bugfix", "fixture"}, []int{2}},
+		{"fix", []string{"hotfix", "bugfix"}, []int{0, 1}},
+		{"zzz", []string{"feature", "bugfix"}, nil},
+		{"feat", nil, nil},
+		{"", []string{"feature"}, nil},
+	}
+	for _, tt := range tests {
+		if got := BestMatches(tt.query, tt.names); !slices.Equal(got, tt.want) {
+			t.Errorf("BestMatches(%q, %q) = %v, want %v", tt.query, tt.names, got, tt.want)
+		}
+	}
+}
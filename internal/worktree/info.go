@@ -17,4 +17,18 @@ type WorktreeInfo struct {
 	Number       int
 	BranchName   string
 	WorktreeName string
+
+	// URL is the web URL of the PR or issue, as returned by gh.
+	URL string
+
+	// Author is the login of the PR or issue author. BaseBranch, HeadOwner, and Draft are the
+	// PR's base branch, the owner of its head repository, and whether it is a draft.
+	Author     string
+	BaseBranch string
+	HeadOwner  string
+	Draft      bool
+
+	// UpstreamRemote and UpstreamBranch, when set, are configured as the branch's upstream.
+	UpstreamRemote string
+	UpstreamBranch string
 }
@@ -0,0 +1,49 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// LinkDirs creates relative symlinks in dstDir to the directories in dirs of srcDir, so that
+// settings such as .vscode are shared with the main worktree. Directories missing from srcDir are
+// skipped, and paths that already exist in dstDir are left alone and returned. On Windows, where
+// creating symlinks often requires extra privileges, or when a symlink cannot be created, the
+// directory is copied instead.
+func LinkDirs(srcDir, dstDir string, dirs []string) (existing []string, err error) {
+	for _, dir := range dirs {
+		if isAbs(dir) || escapes(dir) {
+			return existing, fmt.Errorf("invalid link directory %q: must be inside the repository", dir)
+		}
+		src := filepath.Join(srcDir, dir)
+		if fi, err := os.Stat(src); err != nil || !fi.IsDir() {
+			continue
+		}
+
+		dst := filepath.Join(dstDir, dir)
+		if _, err := os.Lstat(dst); err == nil {
+			existing = append(existing, dir)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return existing, err
+		}
+
+		if runtime.GOOS != "windows" {
+			// Relative links keep working when the worktree base directory is moved together with the repository.
+			target, err := filepath.Rel(filepath.Dir(dst), src)
+			if err != nil {
+				target = src
+			}
+			if err := os.Symlink(target, dst); err == nil {
+				continue
+			}
+		}
+		if err := copyPath(src, dst, nil); err != nil {
+			return existing, fmt.Errorf("failed to copy %s: %w", dir, err)
+		}
+	}
+	return existing, nil
+}
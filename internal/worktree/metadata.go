@@ -0,0 +1,71 @@
+package worktree
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ffalor/gh-wt/internal/git"
+)
+
+// MetadataFile is the name of the metadata file in a worktree's private git directory
+// (.git/worktrees/<name>). Keeping it there hides it from git status and lets it follow
+// the worktree when it is moved.
+const MetadataFile = "gh-worktree.json"
+
+// Metadata records what a worktree was created from, independent of its name.
+type Metadata struct {
+	Type       WorktreeType `json:"type"`
+	Owner      string       `json:"owner,omitempty"`
+	Repo       string       `json:"repo,omitempty"`
+	Number     int          `json:"number,omitempty"`
+	BranchName string       `json:"branch"`
+	CreatedAt  time.Time    `json:"createdAt"`
+}
+
+// WriteMetadata records info in the metadata file of the worktree at worktreePath.
+func WriteMetadata(worktreePath string, info *WorktreeInfo) error {
+	gitDir, err := git.GetGitDir(worktreePath)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(Metadata{
+		Type:       info.Type,
+		Owner:      info.Owner,
+		Repo:       info.Repo,
+		Number:     info.Number,
+		BranchName: info.BranchName,
+		CreatedAt:  time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(gitDir, MetadataFile), append(data, '\n'), 0o644)
+}
+
+// ReadMetadata reads the metadata of the worktree at worktreePath.
+// It returns nil without an error if the worktree has no metadata file.
+func ReadMetadata(worktreePath string) (*Metadata, error) {
+	gitDir, err := git.GetGitDir(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(gitDir, MetadataFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var md Metadata
+	if err := json.Unmarshal(data, &md); err != nil {
+		return nil, fmt.Errorf("invalid %s in %s: %w", MetadataFile, gitDir, err)
+	}
+	return &md, nil
+}
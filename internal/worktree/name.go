@@ -0,0 +1,29 @@
+package worktree
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// invalidNameChars are characters Windows does not allow in file names.
+	invalidNameChars = regexp.MustCompile(`[<>:"|?*\x00-\x1f]`)
+	// reservedName matches Windows device names, which are reserved with any extension.
+	reservedName = regexp.MustCompile(`(?i)^(con|prn|aux|nul|com[0-9]|lpt[0-9])(\..*)?$`)
+)
+
+// SafeName makes name usable as a directory name on every platform, so that worktree
+// layouts also work on Windows. Invalid characters are replaced with "_", trailing dots
+// and spaces are removed, and reserved names such as CON or nul.txt get a "_" suffix on
+// their stem.
+func SafeName(name string) string {
+	if name == "" {
+		return ""
+	}
+	safe := invalidNameChars.ReplaceAllString(name, "_")
+	safe = strings.TrimRight(safe, ". ")
+	if safe == "" {
+		return "_"
+	}
+	return reservedName.ReplaceAllString(safe, "${1}_${2}")
+}
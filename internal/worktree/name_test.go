@@ -0,0 +1,32 @@
+package worktree
+
+import "testing"
+
+func TestSafeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", ""},
+		{"feature-login", "feature-login"},
+		{"pr_123", "pr_123"},
+		{`a<b>c:d"e|f`, "a_b_c_d_e_f"},
+		{"what?", "what_"},
+		{"star*", "star_"},
+		{"tab\there", "tab_here"},
+		{"trailing.", "trailing"},
+		{"trailing. . ", "trailing"},
+		{"...", "_"},
+		{"CON", "CON_"},
+		{"nul.txt", "nul_.txt"},
+		{"Com1", "Com1_"},
+		{"lpt9.tar.gz", "lpt9_.tar.gz"},
+		{"console", "console"},
+		{"ünïcödé", "ünïcödé"},
+	}
+	for _, tt := range tests {
+		if got := SafeName(tt.name); got != tt.want {
+			t.Errorf("SafeName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
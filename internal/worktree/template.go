@@ -0,0 +1,97 @@
+package worktree
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultPathTemplate is the layout used when no path template is configured.
+const DefaultPathTemplate = "{repo}/{name}"
+
+// pathSeparatorReplacer keeps placeholder values from introducing extra directory levels.
+var pathSeparatorReplacer = strings.NewReplacer("/", "_", "\\", "_")
+
+// component returns value as a single directory name that is valid on every platform.
+func component(value string) string {
+	return SafeName(pathSeparatorReplacer.Replace(value))
+}
+
+// Wildcard is a placeholder value that GlobPattern turns into "*". It is a private-use
+// character, which SafeName keeps and worktree names do not contain.
+const Wildcard = "\uE000"
+
+// globEscaper escapes the glob metacharacters in rendered paths. A character class with a
+// single character works as an escape on every platform, unlike "\\", which separates paths on
+// Windows and escapes characters everywhere else.
+var globEscaper = func() *strings.Replacer {
+	pairs := []string{"*", "[*]", "?", "[?]", "[", "[[]", Wildcard, "*"}
+	if filepath.Separator != '\\' {
+		pairs = append(pairs, `\`, `[\\]`)
+	}
+	return strings.NewReplacer(pairs...)
+}()
+
+// GlobPattern returns a filepath.Glob pattern for a path rendered with Wildcard as some of its
+// placeholder values: Wildcard matches any name and everything else matches itself.
+func GlobPattern(rendered string) string {
+	return globEscaper.Replace(rendered)
+}
+
+// placeholders returns the replacer used for rendering name and path templates.
+func placeholders(info *WorktreeInfo) *strings.Replacer {
+	number := ""
+	if info.Number > 0 {
+		number = strconv.Itoa(info.Number)
+	}
+	draft := ""
+	if info.Draft {
+		draft = "draft"
+	}
+	return strings.NewReplacer(
+		"{owner}", component(info.Owner),
+		"{repo}", component(info.Repo),
+		"{number}", number,
+		"{type}", string(info.Type),
+		"{branch}", component(info.BranchName),
+		"{author}", component(info.Author),
+		"{base}", component(info.BaseBranch),
+		"{head_owner}", component(info.HeadOwner),
+		"{draft}", draft,
+		"{name}", info.WorktreeName,
+	)
+}
+
+// RenderName renders a worktree name template such as "{type}_{number}".
+// Path separators are not allowed in the result.
+func RenderName(tmpl string, info *WorktreeInfo) (string, error) {
+	name := component(placeholders(info).Replace(tmpl))
+	if name == "" || name == "." || name == ".." {
+		return "", fmt.Errorf("worktree name template %q rendered an invalid name %q", tmpl, name)
+	}
+	return name, nil
+}
+
+// RenderPath renders a worktree path template relative to baseDir.
+// An empty template uses DefaultPathTemplate. Templates that resolve
+// outside of baseDir are rejected.
+func RenderPath(baseDir, tmpl string, info *WorktreeInfo) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultPathTemplate
+	}
+
+	rendered := placeholders(info).Replace(tmpl)
+	// A drive letter such as C: makes the path absolute or drive-relative on Windows.
+	if filepath.IsAbs(rendered) || filepath.VolumeName(filepath.FromSlash(rendered)) != "" {
+		return "", fmt.Errorf("worktree path template %q must be relative to the worktree directory", tmpl)
+	}
+
+	path := filepath.Join(baseDir, filepath.FromSlash(rendered))
+	rel, err := filepath.Rel(baseDir, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("worktree path template %q escapes the worktree directory %s", tmpl, baseDir)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,77 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRenderPath(t *testing.T) {
+	base := filepath.Join("base", "dir")
+	info := &WorktreeInfo{
+		Type:         PR,
+		Owner:        "cli",
+		Repo:         "cli",
+		Number:       12,
+		BranchName:   "feature/login",
+		WorktreeName: "pr_12",
+	}
+	tests := []struct {
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{"", filepath.Join(base, "cli", "pr_12"), false},
+		{"{owner}/{repo}/{type}-{number}", filepath.Join(base, "cli", "cli", "pr-12"), false},
+		{"{repo}/{branch}", filepath.Join(base, "cli", "feature_login"), false},
+		{"../{name}", "", true},
+		{"{repo}/../..", "", true},
+		{".", "", true},
+		{"/abs/{name}", "", true},
+	}
+	for _, tt := range tests {
+		got, err := RenderPath(base, tt.tmpl, info)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("RenderPath(%q) = %q, %v; want %q, error %v", tt.tmpl, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestGlobPattern(t *testing.T) {
+	base := t.TempDir()
+	// Names with glob metacharacters must only match themselves.
+	for _, dir := range []string{"[r]", "r", "r2/a", "[r]/a", "[r]/b", "other/a"} {
+		if err := os.MkdirAll(filepath.Join(base, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		repo string
+		want []string
+	}{
+		{"[r]", []string{"[r]/a", "[r]/b"}},
+		{"r", nil},
+		{Wildcard, []string{"[r]/a", "[r]/b", "other/a", "r2/a"}},
+	}
+	for _, tt := range tests {
+		path, err := RenderPath(base, "", &WorktreeInfo{Repo: tt.repo, WorktreeName: Wildcard})
+		if err != nil {
+			t.Fatalf("RenderPath() error = %v", err)
+		}
+		matches, err := filepath.Glob(GlobPattern(path))
+		if err != nil {
+			t.Fatalf("Glob(%q) error = %v", GlobPattern(path), err)
+		}
+		var got []string
+		for _, match := range matches {
+			rel, _ := filepath.Rel(base, match)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("repo %q matched %v, want %v", tt.repo, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+//go:build windows
+
+package worktree
+
+import "testing"
+
+func TestRenderPathWindows(t *testing.T) {
+	info := &WorktreeInfo{
+		Type:         Issue,
+		Owner:        "org",
+		Repo:         "CON",
+		Number:       7,
+		BranchName:   `fix\windows`,
+		WorktreeName: "issue_7.",
+	}
+	tests := []struct {
+		base    string
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{`C:\worktrees`, "", `C:\worktrees\CON_\issue_7.`, false},
+		{`C:\worktrees`, "{owner}/{repo}/{branch}", `C:\worktrees\org\CON_\fix_windows`, false},
+		{`\\server\share\wt`, "{repo}", `\\server\share\wt\CON_`, false},
+		{`C:\worktrees`, "D:/{name}", "", true},
+		{`C:\worktrees`, "C:{name}", "", true},
+		{`C:\worktrees`, `..\{name}`, "", true},
+	}
+	for _, tt := range tests {
+		got, err := RenderPath(tt.base, tt.tmpl, info)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("RenderPath(%q, %q) = %q, %v; want %q, error %v", tt.base, tt.tmpl, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRenderNameWindows(t *testing.T) {
+	info := &WorktreeInfo{Type: PR, Number: 3, BranchName: "aux"}
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{"{branch}", "aux_"},
+		{"{type}_{number}.", "pr_3"},
+		{"{branch}?*", "aux__"},
+	}
+	for _, tt := range tests {
+		if got, err := RenderName(tt.tmpl, info); err != nil || got != tt.want {
+			t.Errorf("RenderName(%q) = %q, %v; want %q", tt.tmpl, got, err, tt.want)
+		}
+	}
+}
+
+func TestGlobPatternWindows(t *testing.T) {
+	// Backslashes separate paths on Windows and must not be escaped.
+	got := GlobPattern(`C:\wt\[repo]\` + Wildcard)
+	if want := `C:\wt\[[]repo]\*`; got != want {
+		t.Errorf("GlobPattern() = %q, want %q", got, want)
+	}
+}
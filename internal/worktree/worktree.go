@@ -1,6 +1,7 @@
 package worktree
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,9 +12,11 @@ import (
 
 // Create creates a new worktree.
 // path: The absolute path where the worktree should be created.
-// branch: The exact name of the branch to create.
-// startPoint: The ref to start from (e.g., HEAD, FETCH_HEAD, an existing branch).
-func Create(path, branch, startPoint string) error {
+// branch: The exact name of the branch to create. If empty, the worktree is detached at startPoint.
+// startPoint: The ref to start from (e.g., HEAD, FETCH_HEAD, an existing branch). If empty, an
+// existing branch is checked out as is.
+// track: Whether the branch tracks startPoint; only used when startPoint is given.
+func Create(ctx context.Context, path, branch, startPoint string, track git.TrackMode) error {
 	var err error
 
 	// Ensure the base directory exists
@@ -25,15 +28,20 @@ func Create(path, branch, startPoint string) error {
 	// Check if git still has a record of this worktree (even though it doesn't exist on disk)
 	// and remove it if necessary
 	if git.WorktreeIsRegistered(path) {
-		if err = git.WorktreeRemove(path, true); err != nil {
+		if err = git.WorktreeRemove(ctx, path, true); err != nil {
 			return fmt.Errorf("failed to remove stale worktree record: %w", err)
 		}
 	}
 
-	if startPoint != "" {
-		err = git.WorktreeAddFromRef(branch, path, startPoint)
-	} else {
-		err = git.WorktreeAdd(branch, path)
+	switch {
+	case branch == "":
+		err = git.WorktreeAddDetached(ctx, path, startPoint)
+	case startPoint != "":
+		err = git.WorktreeAddFromRef(ctx, branch, path, startPoint, track)
+	case git.BranchExists(branch):
+		err = git.WorktreeAddFromBranch(ctx, branch, path)
+	default:
+		err = git.WorktreeAdd(ctx, branch, path)
 	}
 
 	if err != nil {
@@ -43,9 +51,26 @@ func Create(path, branch, startPoint string) error {
 	return nil
 }
 
+// EnsureDir creates dir if it is missing and checks that it is a writable directory.
+func EnsureDir(dir string) error {
+	if fi, err := os.Stat(dir); err == nil && !fi.IsDir() {
+		return fmt.Errorf("%s exists but is not a directory", dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("cannot create directory %s: %w", dir, err)
+	}
+
+	f, err := os.CreateTemp(dir, ".gh-wt-write-test-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable: %w", dir, err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
 // Remove removes a worktree.
 // This function is responsible for running `git worktree remove` and ensuring the directory is gone.
-func Remove(path string, force bool) error {
+func Remove(ctx context.Context, path string, force bool) error {
 	// Check for uncommitted changes if not forced
 	if !force && git.HasUncommittedChanges(path) {
 		return fmt.Errorf("worktree has uncommitted changes")
@@ -65,7 +90,7 @@ func Remove(path string, force bool) error {
 
 	// Remove worktree from git records
 	if exactPath != "" {
-		if err := git.WorktreeRemove(exactPath, force); err != nil {
+		if err := git.WorktreeRemove(ctx, exactPath, force); err != nil {
 			// If git worktree remove fails, try manual removal as a fallback
 			if err := os.RemoveAll(path); err != nil {
 				return err
@@ -0,0 +1,155 @@
+package ghwt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/worktree"
+)
+
+// CreateOptions configures Create.
+type CreateOptions struct {
+	// Path is where the worktree is created. It must not be an existing worktree unless Overwrite is set.
+	Path string
+	// StartPoint is the ref the new branch starts from. If empty, info.BranchName must already exist.
+	StartPoint string
+	// Detach creates a worktree without a branch, checked out at StartPoint.
+	Detach bool
+	// Track controls whether the new branch tracks StartPoint. TrackNever also skips
+	// setting info.UpstreamRemote as the upstream.
+	Track TrackMode
+	// Overwrite first removes whatever is at Path: a worktree, a stale worktree record, or a plain directory.
+	Overwrite bool
+	// OverwriteBranch first deletes info.BranchName if it exists, so that it is created afresh from StartPoint.
+	OverwriteBranch bool
+	// SparsePaths limits the checkout to these directories with a cone-mode sparse-checkout.
+	SparsePaths []string
+	// Checkout, if set, runs after the worktree is created at StartPoint, with the worktree's
+	// absolute path, to check out something else in it, such as a PR with gh pr checkout.
+	// If it fails, the worktree is removed again.
+	Checkout func(ctx context.Context, path string) error
+}
+
+// CreateResult describes a created worktree.
+type CreateResult struct {
+	// Path is the absolute path of the worktree.
+	Path string
+	// Warnings are the steps after creating the worktree that failed, such as setting the upstream.
+	Warnings []error
+}
+
+// ValidateBranchName returns an error if name is not a valid git branch name. PR head branches
+// and linked branches come from GitHub unsanitized, so they are checked before doing any work.
+func ValidateBranchName(name string) error {
+	if !git.IsValidRefName(name) {
+		return fmt.Errorf("'%s' is not a valid git branch name (see git check-ref-format)", name)
+	}
+	return nil
+}
+
+// Create creates the worktree described by info: it checks out info.BranchName, creating it from
+// StartPoint if needed, sets the upstream to info.UpstreamRemote and info.UpstreamBranch, and
+// records info as the worktree's metadata. If creating the worktree fails, the directory and the
+// branch are removed again, unless the branch existed before.
+func Create(ctx context.Context, info *Info, opts CreateOptions) (*CreateResult, error) {
+	if !opts.Detach {
+		if err := ValidateBranchName(info.BranchName); err != nil {
+			return nil, err
+		}
+	}
+
+	absPath, err := filepath.Abs(opts.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := worktree.EnsureDir(filepath.Dir(absPath)); err != nil {
+		return nil, fmt.Errorf("invalid worktree directory: %w", err)
+	}
+	if opts.Overwrite {
+		if err := clearPath(ctx, absPath); err != nil {
+			return nil, err
+		}
+	}
+	if opts.OverwriteBranch && git.BranchExists(info.BranchName) {
+		if err := git.BranchDelete(info.BranchName, true); err != nil {
+			return nil, fmt.Errorf("failed to delete branch: %w", err)
+		}
+	}
+
+	branch := info.BranchName
+	if opts.Detach {
+		branch = ""
+	}
+	if opts.Detach || opts.Track == TrackNever {
+		info.UpstreamRemote = ""
+	}
+
+	// Remember whether the branch exists so that cleanup never deletes a branch that was already there.
+	// Checkout may create info.BranchName even in a detached worktree.
+	branchExisted := info.BranchName == "" || git.BranchExists(info.BranchName)
+	rollback := func(err error) error {
+		// Leave no residue: remove the directory and the branch if this attempt created them.
+		if worktree.Exists(absPath) {
+			os.RemoveAll(absPath)
+		}
+		if git.WorktreeIsRegistered(absPath) {
+			_ = git.WorktreePrune(context.Background())
+		}
+		if !branchExisted && git.BranchExists(info.BranchName) {
+			if delErr := git.BranchDelete(info.BranchName, true); delErr != nil {
+				return fmt.Errorf("%w (and failed to delete branch '%s' afterwards: %v)", err, info.BranchName, delErr)
+			}
+		}
+		return err
+	}
+	if err := worktree.Create(ctx, absPath, branch, opts.StartPoint, opts.Track); err != nil {
+		return nil, rollback(err)
+	}
+	if opts.Checkout != nil {
+		if err := opts.Checkout(ctx, absPath); err != nil {
+			return nil, rollback(err)
+		}
+	}
+
+	result := &CreateResult{Path: absPath}
+	if len(opts.SparsePaths) > 0 {
+		if err := git.SparseCheckout(ctx, absPath, opts.SparsePaths); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Errorf("failed to set up sparse-checkout: %w", err))
+		}
+	}
+
+	if info.UpstreamRemote != "" {
+		if err := git.SetUpstream(info.BranchName, info.UpstreamRemote, info.UpstreamBranch); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Errorf("failed to set upstream for '%s': %w", info.BranchName, err))
+		}
+	}
+
+	if err := worktree.WriteMetadata(absPath, info); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Errorf("failed to record worktree metadata: %w", err))
+	}
+
+	return result, nil
+}
+
+// clearPath removes the worktree, stale worktree record, or directory at absPath.
+func clearPath(ctx context.Context, absPath string) error {
+	registered := git.WorktreeIsRegistered(absPath)
+	switch {
+	case worktree.Exists(absPath) && registered:
+		if err := git.WorktreeRemove(ctx, absPath, true); err != nil {
+			return fmt.Errorf("failed to remove worktree: %w", err)
+		}
+	case worktree.Exists(absPath):
+		if err := os.RemoveAll(absPath); err != nil {
+			return fmt.Errorf("failed to remove directory: %w", err)
+		}
+	case registered:
+		if err := git.WorktreePrune(ctx); err != nil {
+			return fmt.Errorf("failed to prune worktree: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package ghwt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ffalor/gh-wt/internal/git"
+)
+
+// setupRepo creates a repository with one commit and makes it the current directory.
+func setupRepo(t *testing.T) string {
+	t.Helper()
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(root, ".gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	repo := filepath.Join(root, "r")
+	runGit(t, root, "init", "-q", "-b", "main", repo)
+	runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "init")
+	t.Chdir(repo)
+	return repo
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestCreate(t *testing.T) {
+	repo := setupRepo(t)
+	path := filepath.Join(filepath.Dir(repo), "wt", "feature")
+	info := &Info{Type: Local, Repo: "r", BranchName: "feature", WorktreeName: "feature"}
+
+	result, err := Create(context.Background(), info, CreateOptions{Path: path, StartPoint: "HEAD"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if result.Path != path || len(result.Warnings) != 0 {
+		t.Errorf("Create() = %+v, want path %s without warnings", result, path)
+	}
+	if branch, _ := git.GetCurrentBranch(path); branch != "feature" {
+		t.Errorf("worktree is on branch %q, want %q", branch, "feature")
+	}
+}
+
+func TestCreateRollback(t *testing.T) {
+	failed := errors.New("checkout failed")
+	failingCheckout := func(ctx context.Context, path string) error {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Checkout ran before the worktree was created: %v", err)
+		}
+		return failed
+	}
+
+	tests := []struct {
+		name       string
+		existing   bool
+		startPoint string
+	}{
+		{"new branch is deleted", false, "HEAD"},
+		{"existing branch is kept", true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := setupRepo(t)
+			if tt.existing {
+				runGit(t, repo, "branch", "feature")
+			}
+			path := filepath.Join(filepath.Dir(repo), "wt", "feature")
+			info := &Info{Type: Local, Repo: "r", BranchName: "feature", WorktreeName: "feature"}
+
+			_, err := Create(context.Background(), info, CreateOptions{
+				Path:       path,
+				StartPoint: tt.startPoint,
+				Checkout:   failingCheckout,
+			})
+			if !errors.Is(err, failed) {
+				t.Fatalf("Create() error = %v, want %v", err, failed)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("worktree directory was left behind: %v", err)
+			}
+			if git.WorktreeIsRegistered(path) {
+				t.Error("worktree is still registered")
+			}
+			if got := git.BranchExists("feature"); got != tt.existing {
+				t.Errorf("branch exists = %v, want %v", got, tt.existing)
+			}
+		})
+	}
+}
+
+func TestCreateInvalidBranchName(t *testing.T) {
+	repo := setupRepo(t)
+	path := filepath.Join(filepath.Dir(repo), "wt", "bad")
+	info := &Info{Type: PR, Repo: "r", Number: 1, BranchName: "bad..name", WorktreeName: "bad"}
+
+	if _, err := Create(context.Background(), info, CreateOptions{Path: path, StartPoint: "HEAD"}); err == nil {
+		t.Fatal("Create() with an invalid branch name succeeded")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("worktree directory was created: %v", err)
+	}
+}
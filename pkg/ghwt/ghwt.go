@@ -0,0 +1,40 @@
+// Package ghwt exposes the core operations of gh-worktree for use as a library: parsing pull
+// request and issue references, computing worktree paths, and creating and removing worktrees.
+//
+// The functions never prompt or print. Like git itself, they operate on the repository in the
+// current working directory. Problems that do not prevent an operation from succeeding are
+// returned as warnings in the result instead of as errors.
+package ghwt
+
+import (
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/worktree"
+)
+
+// Type is the kind of worktree: a pull request, an issue, or a local branch.
+type Type = worktree.WorktreeType
+
+const (
+	PR    = worktree.PR
+	Issue = worktree.Issue
+	Local = worktree.Local
+)
+
+// Commit is the type DetermineType reports for commit URLs. Worktrees for a commit are
+// created like local worktrees of that commit, so Commit is never recorded in metadata.
+const Commit Type = "commit"
+
+// Info describes the worktree to create. It is also what name and path templates are rendered from.
+type Info = worktree.WorktreeInfo
+
+// Worktree is a worktree registered with git.
+type Worktree = git.WorktreeInfo
+
+// TrackMode controls whether a new branch tracks its start point.
+type TrackMode = git.TrackMode
+
+const (
+	TrackDefault = git.TrackDefault
+	TrackAlways  = git.TrackAlways
+	TrackNever   = git.TrackNever
+)
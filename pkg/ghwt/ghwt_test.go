@@ -0,0 +1,28 @@
+package ghwt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMain points gh at a config that knows github.com and ghe.example.com, so that tests do
+// not depend on the hosts the user is logged in to. go-gh reads its config only once.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "gh-config")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	hosts := "github.com:\n    user: test\nghe.example.com:\n    user: test\n"
+	if err := os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0o600); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Setenv("GH_CONFIG_DIR", dir)
+	os.Unsetenv("GH_HOST")
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
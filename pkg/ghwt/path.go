@@ -0,0 +1,56 @@
+package ghwt
+
+import (
+	"fmt"
+
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/worktree"
+)
+
+// Layout is where worktrees are placed.
+type Layout struct {
+	// BaseDir is the directory all worktrees live under.
+	BaseDir string
+	// PathTemplate is the path of a worktree relative to BaseDir, such as "{repo}/{name}".
+	// An empty template uses the default layout.
+	PathTemplate string
+	// NameTemplate, when set, names PR and issue worktrees, such as "{type}_{number}".
+	NameTemplate string
+}
+
+// Path returns the path of the worktree for info. For PR and issue worktrees, the name
+// template is rendered into info.WorktreeName first.
+func Path(layout Layout, info *Info) (string, error) {
+	if layout.NameTemplate != "" && info.Type != Local {
+		name, err := worktree.RenderName(layout.NameTemplate, info)
+		if err != nil {
+			return "", err
+		}
+		info.WorktreeName = name
+	}
+	return worktree.RenderPath(layout.BaseDir, layout.PathTemplate, info)
+}
+
+// NextFreePath appends -2, -3, ... to info.WorktreeName until the path is free.
+// Local branches are suffixed as well since they are named after the worktree.
+func NextFreePath(layout Layout, info *Info) (string, error) {
+	baseName, baseBranch := info.WorktreeName, info.BranchName
+	for i := 2; ; i++ {
+		info.WorktreeName = fmt.Sprintf("%s-%d", baseName, i)
+		if info.Type == Local {
+			info.BranchName = fmt.Sprintf("%s-%d", baseBranch, i)
+		}
+
+		path, err := worktree.RenderPath(layout.BaseDir, layout.PathTemplate, info)
+		if err != nil {
+			return "", err
+		}
+		if worktree.Exists(path) || git.WorktreeIsRegistered(path) {
+			continue
+		}
+		if info.Type == Local && git.BranchExists(info.BranchName) {
+			continue
+		}
+		return path, nil
+	}
+}
@@ -0,0 +1,162 @@
+package ghwt
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// Ref is a GitHub pull request, issue, or commit.
+type Ref struct {
+	Host   string
+	Owner  string
+	Repo   string
+	Type   Type
+	Number int
+}
+
+// URL returns the canonical URL of the pull request or issue.
+func (r Ref) URL() string {
+	kind := "pull"
+	if r.Type == Issue {
+		kind = "issues"
+	}
+	return fmt.Sprintf("https://%s/%s/%s/%s/%d", r.Host, r.Owner, r.Repo, kind, r.Number)
+}
+
+// ParseURL parses a pull request or issue URL.
+// It tolerates "www." hosts, trailing slashes, query strings, fragments,
+// extra path segments like "/files", and ".git" suffixes.
+func ParseURL(input string) (Ref, bool) {
+	host, parts, ok := splitURL(input)
+	if !ok || len(parts) < 4 {
+		return Ref{}, false
+	}
+
+	ref := Ref{
+		Host:  host,
+		Owner: parts[0],
+		Repo:  strings.TrimSuffix(parts[1], ".git"),
+	}
+
+	switch parts[2] {
+	case "pull", "pulls":
+		ref.Type = PR
+	case "issues":
+		ref.Type = Issue
+	default:
+		return Ref{}, false
+	}
+
+	var err error
+	ref.Number, err = strconv.Atoi(strings.TrimSuffix(parts[3], ".git"))
+	if err != nil || ref.Number <= 0 || ref.Repo == "" {
+		return Ref{}, false
+	}
+
+	return ref, true
+}
+
+// commitSHAPattern matches the full or abbreviated commit hashes in commit URLs.
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,64}$`)
+
+// ParseCommitURL parses a commit URL such as https://github.com/owner/repo/commit/<sha>.
+// The returned Ref has the Commit type and no number.
+func ParseCommitURL(input string) (ref Ref, sha string, ok bool) {
+	host, parts, ok := splitURL(input)
+	if !ok || len(parts) < 4 || parts[2] != "commit" {
+		return Ref{}, "", false
+	}
+
+	ref = Ref{
+		Host:  host,
+		Owner: parts[0],
+		Repo:  strings.TrimSuffix(parts[1], ".git"),
+		Type:  Commit,
+	}
+	sha = parts[3]
+	if ref.Repo == "" || !commitSHAPattern.MatchString(sha) {
+		return Ref{}, "", false
+	}
+	return ref, sha, true
+}
+
+// splitURL returns the normalized host and the non-empty path segments of an http(s) URL.
+func splitURL(input string) (host string, parts []string, ok bool) {
+	u, err := url.Parse(strings.TrimSpace(input))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return "", nil, false
+	}
+	for _, part := range strings.Split(u.Path, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www."), parts, true
+}
+
+// shorthandPattern matches owner/repo#123 and #123 references.
+var shorthandPattern = regexp.MustCompile(`^(?:([A-Za-z0-9_.-]+)/([A-Za-z0-9_.-]+))?#(\d+)$`)
+
+// ParseShorthand parses an owner/repo#123 or #123 reference. Owner and repo are empty for #123.
+func ParseShorthand(input string) (owner, repo string, number int, ok bool) {
+	m := shorthandPattern.FindStringSubmatch(strings.TrimSpace(input))
+	if m == nil {
+		return "", "", 0, false
+	}
+	number, err := strconv.Atoi(m[3])
+	if err != nil || number <= 0 {
+		return "", "", 0, false
+	}
+	return m[1], m[2], number, true
+}
+
+// DetermineType reports whether input is a pull request URL, an issue URL, a commit URL, or a
+// local name. Pull request, issue, and commit URLs must point at a GitHub host gh knows about.
+// Other URLs on GitHub hosts, such as discussions, are rejected; URLs on other hosts that do
+// not look like GitHub links, such as GitLab merge requests, are local names.
+func DetermineType(input string) (Type, error) {
+	host, parts, ok := splitURL(input)
+	if !ok {
+		return Local, nil
+	}
+	if !IsGitHubHost(host) {
+		_, isRef := ParseURL(input)
+		_, _, isCommit := ParseCommitURL(input)
+		if !isRef && !isCommit {
+			return Local, nil
+		}
+		return Local, fmt.Errorf("'%s' is not a known GitHub host; authenticate with 'gh auth login --hostname %s' to use it", host, host)
+	}
+	if ref, ok := ParseURL(input); ok {
+		return ref.Type, nil
+	}
+	if _, _, ok := ParseCommitURL(input); ok {
+		return Commit, nil
+	}
+	if len(parts) >= 3 {
+		return Local, fmt.Errorf("unsupported URL type '%s' in %s: only pull request, issue, and commit URLs are supported", parts[2], input)
+	}
+	return Local, fmt.Errorf("unsupported URL %s: only pull request, issue, and commit URLs are supported", input)
+}
+
+// IsGitHubHost reports whether host is github.com, a GitHub tenancy host,
+// or a host configured in gh (including GH_HOST).
+func IsGitHubHost(host string) bool {
+	host = auth.NormalizeHostname(host)
+	if host == "github.com" || auth.IsTenancy(host) {
+		return true
+	}
+
+	defaultHost, _ := auth.DefaultHost()
+	for _, known := range append(auth.KnownHosts(), defaultHost) {
+		if auth.NormalizeHostname(known) == host {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package ghwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ffalor/gh-wt/internal/git"
+	"github.com/ffalor/gh-wt/internal/worktree"
+)
+
+var (
+	// ErrLocked is returned when removing a locked worktree.
+	ErrLocked = errors.New("worktree is locked")
+	// ErrUncommittedChanges is returned when removing a worktree with uncommitted changes without Force.
+	ErrUncommittedChanges = errors.New("worktree has uncommitted changes")
+	// ErrBranchNotDeleted is returned when the worktree was removed but its branch could not be deleted,
+	// usually because it has unmerged commits.
+	ErrBranchNotDeleted = errors.New("failed to delete branch")
+)
+
+// RemoveOptions configures Remove.
+type RemoveOptions struct {
+	// Force removes the worktree even if it has uncommitted changes.
+	Force bool
+	// KeepBranch keeps the worktree's branch.
+	KeepBranch bool
+	// ForceBranch deletes the branch even if it has unmerged commits.
+	ForceBranch bool
+}
+
+// RemoveResult describes what Remove did.
+type RemoveResult struct {
+	// Branch is the branch that was checked out in the worktree, if any.
+	Branch string
+	// BranchDeleted reports whether Branch was deleted.
+	BranchDeleted bool
+	// BranchInMainWorktree reports that Branch was kept because it is checked out in the main worktree.
+	BranchInMainWorktree bool
+}
+
+// Remove removes the worktree wt and, unless opts.KeepBranch is set, deletes its branch.
+// The branch checked out in the main worktree is never deleted. If the worktree is removed but
+// its branch is not, the result is returned together with an error wrapping ErrBranchNotDeleted.
+func Remove(ctx context.Context, wt Worktree, opts RemoveOptions) (*RemoveResult, error) {
+	if wt.Locked {
+		return nil, ErrLocked
+	}
+	if !opts.Force && git.HasUncommittedChanges(wt.Path) {
+		return nil, ErrUncommittedChanges
+	}
+
+	if err := worktree.Remove(ctx, wt.Path, opts.Force); err != nil {
+		return nil, fmt.Errorf("failed to remove worktree: %w", err)
+	}
+
+	result := &RemoveResult{Branch: wt.Branch}
+	if wt.Branch == "" || opts.KeepBranch {
+		return result, nil
+	}
+
+	if mainPath, err := git.GetMainWorktreePath(); err == nil {
+		if mainBranch, err := git.GetCurrentBranch(mainPath); err == nil && mainBranch == wt.Branch {
+			result.BranchInMainWorktree = true
+			return result, nil
+		}
+	}
+
+	// The primary goal, removing the worktree, succeeded even if git refuses to delete the branch.
+	if err := git.BranchDelete(wt.Branch, opts.ForceBranch); err != nil {
+		return result, fmt.Errorf("worktree removed, but %w '%s': %v", ErrBranchNotDeleted, wt.Branch, err)
+	}
+	result.BranchDeleted = true
+	return result, nil
+}
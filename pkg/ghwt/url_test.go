@@ -0,0 +1,145 @@
+package ghwt
+
+import "testing"
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Ref
+		ok    bool
+	}{
+		{"https://github.com/cli/cli/pull/123", Ref{"github.com", "cli", "cli", PR, 123}, true},
+		{"https://github.com/cli/cli/pull/123/", Ref{"github.com", "cli", "cli", PR, 123}, true},
+		{"https://github.com/cli/cli/pull/123/files", Ref{"github.com", "cli", "cli", PR, 123}, true},
+		{"https://github.com/cli/cli/pull/123#discussion_r456", Ref{"github.com", "cli", "cli", PR, 123}, true},
+		{"https://github.com/cli/cli/pull/123?diff=split", Ref{"github.com", "cli", "cli", PR, 123}, true},
+		{"https://github.com/cli/cli/pulls/123", Ref{"github.com", "cli", "cli", PR, 123}, true},
+		{"https://www.github.com/cli/cli/pull/123", Ref{"github.com", "cli", "cli", PR, 123}, true},
+		{"https://GitHub.com/cli/cli.git/pull/123", Ref{"github.com", "cli", "cli", PR, 123}, true},
+		{"  https://github.com/cli/cli/issues/7  ", Ref{"github.com", "cli", "cli", Issue, 7}, true},
+		{"https://github.com/cli/cli/issues/7#issuecomment-1", Ref{"github.com", "cli", "cli", Issue, 7}, true},
+		{"http://github.example.com/org/repo/pull/5/commits", Ref{"github.example.com", "org", "repo", PR, 5}, true},
+		{"https://github.example.com:8443/org/repo/issues/9", Ref{"github.example.com", "org", "repo", Issue, 9}, true},
+		{"https://github.com/cli/cli/pull/0", Ref{}, false},
+		{"https://github.com/cli/cli/pull/abc", Ref{}, false},
+		{"https://github.com/cli/cli/discussions/1", Ref{}, false},
+		{"https://github.com/cli/cli/pull", Ref{}, false},
+		{"ftp://github.com/cli/cli/pull/1", Ref{}, false},
+		{"github.com/cli/cli/pull/1", Ref{}, false},
+		{"feature/login", Ref{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseURL(tt.input)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseURL(%q) = %+v, %v; want %+v, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRefURL(t *testing.T) {
+	tests := []struct {
+		ref  Ref
+		want string
+	}{
+		{Ref{"github.com", "cli", "cli", PR, 1}, "https://github.com/cli/cli/pull/1"},
+		{Ref{"github.example.com", "org", "repo", Issue, 2}, "https://github.example.com/org/repo/issues/2"},
+	}
+	for _, tt := range tests {
+		if got := tt.ref.URL(); got != tt.want {
+			t.Errorf("%+v.URL() = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestParseCommitURL(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Ref
+		sha   string
+		ok    bool
+	}{
+		{"https://github.com/cli/cli/commit/abc1234", Ref{"github.com", "cli", "cli", Commit, 0}, "abc1234", true},
+		{"https://github.com/cli/cli/commit/0123456789abcdef0123456789abcdef01234567/", Ref{"github.com", "cli", "cli", Commit, 0}, "0123456789abcdef0123456789abcdef01234567", true},
+		{"https://www.github.com/cli/cli.git/commit/ABC1234?diff=unified#diff-1", Ref{"github.com", "cli", "cli", Commit, 0}, "ABC1234", true},
+		{"https://github.example.com/org/repo/commit/deadbeef", Ref{"github.example.com", "org", "repo", Commit, 0}, "deadbeef", true},
+		{"https://github.com/cli/cli/commit/abc12", Ref{}, "", false},
+		{"https://github.com/cli/cli/commit/not-a-sha", Ref{}, "", false},
+		{"https://github.com/cli/cli/commits/abc1234", Ref{}, "", false},
+		{"https://github.com/cli/cli/pull/1", Ref{}, "", false},
+	}
+	for _, tt := range tests {
+		got, sha, ok := ParseCommitURL(tt.input)
+		if ok != tt.ok || got != tt.want || sha != tt.sha {
+			t.Errorf("ParseCommitURL(%q) = %+v, %q, %v; want %+v, %q, %v", tt.input, got, sha, ok, tt.want, tt.sha, tt.ok)
+		}
+	}
+}
+
+func TestParseShorthand(t *testing.T) {
+	tests := []struct {
+		input  string
+		owner  string
+		repo   string
+		number int
+		ok     bool
+	}{
+		{"cli/cli#123", "cli", "cli", 123, true},
+		{"my-org/my.repo_2#7", "my-org", "my.repo_2", 7, true},
+		{"#42", "", "", 42, true},
+		{" #42 ", "", "", 42, true},
+		{"#0", "", "", 0, false},
+		{"#", "", "", 0, false},
+		{"cli#1", "", "", 0, false},
+		{"cli/cli#abc", "", "", 0, false},
+		{"cli/cli/extra#1", "", "", 0, false},
+		{"feature/login", "", "", 0, false},
+		{"123", "", "", 0, false},
+	}
+	for _, tt := range tests {
+		owner, repo, number, ok := ParseShorthand(tt.input)
+		if owner != tt.owner || repo != tt.repo || number != tt.number || ok != tt.ok {
+			t.Errorf("ParseShorthand(%q) = %q, %q, %d, %v; want %q, %q, %d, %v",
+				tt.input, owner, repo, number, ok, tt.owner, tt.repo, tt.number, tt.ok)
+		}
+	}
+}
+
+func TestDetermineType(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Type
+		wantErr bool
+	}{
+		{"feature/login", Local, false},
+		{"pr_123", Local, false},
+		{"https://github.com/cli/cli/pull/1", PR, false},
+		{"https://github.com/cli/cli/issues/2", Issue, false},
+		{"https://github.com/cli/cli/commit/abc1234", Commit, false},
+		{"https://ghe.example.com/org/repo/pull/3/files", PR, false},
+		{"https://acme.ghe.com/org/repo/issues/4", Issue, false},
+		{"https://github.com/cli/cli/discussions/5", Local, true},
+		{"https://github.com/cli/cli", Local, true},
+		{"https://gitlab.com/group/project/-/merge_requests/6", Local, false},
+		{"https://gitlab.com/group/project/-/issues/6", Local, false},
+		{"https://unknown.example.com/org/repo/pull/7", Local, true},
+		{"https://unknown.example.com/org/repo/issues/7", Local, true},
+		{"https://unknown.example.com/org/repo/commit/abc1234", Local, true},
+	}
+	for _, tt := range tests {
+		got, err := DetermineType(tt.input)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("DetermineType(%q) = %q, %v; want %q, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestDetermineTypeGHHost(t *testing.T) {
+	input := "https://github.internal.example/org/repo/pull/8"
+	if _, err := DetermineType(input); err == nil {
+		t.Fatalf("DetermineType(%q) accepted a host gh does not know", input)
+	}
+	t.Setenv("GH_HOST", "github.internal.example")
+	if got, err := DetermineType(input); err != nil || got != PR {
+		t.Errorf("DetermineType(%q) with GH_HOST = %q, %v; want %q", input, got, err, PR)
+	}
+}